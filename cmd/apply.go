@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var (
+	manifestPaths []string
+	prune         bool
+	dryRun        bool
+)
+
+// Plan actions
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// change brings one vm in line with the manifests
+type change struct {
+	action string
+	vm     *pbv2.Virtualmachine // desired, the current vm for deletes
+	old    *pbv2.Virtualmachine // current, nil for creates
+}
+
+// fieldChange is a field that differs between the server and the manifests
+type fieldChange struct {
+	field, old, new string
+}
+
+// vmFieldChanges lists the fields of old that differ in vm
+func vmFieldChanges(old, vm *pbv2.Virtualmachine) []fieldChange {
+	var fcs []fieldChange
+	if old.Project != vm.Project {
+		fcs = append(fcs, fieldChange{"project", old.Project, vm.Project})
+	}
+	if old.Role != vm.Role {
+		fcs = append(fcs, fieldChange{"role", old.Role, vm.Role})
+	}
+	if o, n := formatLabels(old.Labels, ","), formatLabels(vm.Labels, ","); o != n {
+		fcs = append(fcs, fieldChange{"labels", o, n})
+	}
+	// Manifests without addresses keep the ones the vm has, e.g. from ipam
+	if o, n := joinSorted(old.Addresses), joinSorted(vm.Addresses); len(vm.Addresses) > 0 && o != n {
+		fcs = append(fcs, fieldChange{"addresses", o, n})
+	}
+	// Manifests without a sizing keep the one the vm has, e.g. its role's
+	if o, n := formatSizing(old.Sizing), formatSizing(vm.Sizing); vm.Sizing != nil && o != n {
+		fcs = append(fcs, fieldChange{"sizing", o, n})
+	}
+	return fcs
+}
+
+// joinSorted lists ss in order, for comparing fields whose order the server
+// does not keep
+func joinSorted(ss []string) string {
+	ss = append([]string(nil), ss...)
+	sort.Strings(ss)
+	return strings.Join(ss, ",")
+}
+
+// keepRoleLabels gives desired vms the labels their role defaults that the
+// manifests leave out, as the live vms have them. The server fills these in
+// on create, so manifests need not repeat them.
+func keepRoleLabels(desired, current []*pbv2.Virtualmachine, roles []*pbv2.Role) {
+	defaults := map[string]map[string]string{}
+	for _, r := range roles {
+		defaults[r.Name] = r.DefaultLabels
+	}
+	byHost := map[string]*pbv2.Virtualmachine{}
+	for _, vm := range current {
+		byHost[vm.Hostname] = vm
+	}
+
+	for _, vm := range desired {
+		old, ok := byHost[vm.Hostname]
+		if !ok {
+			continue
+		}
+		for name := range defaults[vm.Role] {
+			value, live := old.Labels[name]
+			if _, set := vm.Labels[name]; set || !live {
+				continue
+			}
+			if vm.Labels == nil {
+				vm.Labels = map[string]string{}
+			}
+			vm.Labels[name] = value
+		}
+	}
+}
+
+// livePlan works out the changes that make the server match desired
+func livePlan(desired []*pbv2.Virtualmachine, prune bool) ([]change, error) {
+	current, err := listAllVMs()
+	if err != nil {
+		return nil, err
+	}
+	r, err := roles.ListRoles(ctx, &pbv2.ListRolesRequest{})
+	if err != nil {
+		return nil, callError(err, "could not list roles")
+	}
+	keepRoleLabels(desired, current, r.Roles)
+	return makePlan(desired, current, prune), nil
+}
+
+// makePlan works out the changes that make current match desired. With prune
+// vms missing from desired are deleted, but only in projects desired has vms
+// in, so a manifest for one project never deletes another's vms.
+func makePlan(desired, current []*pbv2.Virtualmachine, prune bool) []change {
+	byHost := map[string]*pbv2.Virtualmachine{}
+	for _, vm := range current {
+		byHost[vm.Hostname] = vm
+	}
+
+	var plan []change
+	wanted := map[string]bool{}
+	projects := map[string]bool{}
+	for _, vm := range desired {
+		wanted[vm.Hostname] = true
+		projects[vm.Project] = true
+		old, ok := byHost[vm.Hostname]
+		switch {
+		case !ok:
+			plan = append(plan, change{action: actionCreate, vm: vm})
+		case len(vmFieldChanges(old, vm)) > 0:
+			plan = append(plan, change{action: actionUpdate, vm: vm, old: old})
+		}
+	}
+
+	if prune {
+		for _, vm := range current {
+			if !wanted[vm.Hostname] && projects[vm.Project] {
+				plan = append(plan, change{action: actionDelete, vm: vm, old: vm})
+			}
+		}
+	}
+
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].vm.Hostname < plan[j].vm.Hostname
+	})
+	return plan
+}
+
+// writePlan prints what plan will do
+func writePlan(w io.Writer, plan []change) {
+	counts := map[string]int{}
+	for _, ch := range plan {
+		counts[ch.action]++
+		switch ch.action {
+		case actionCreate:
+			fmt.Fprintf(w, "+ create %s (project=%s role=%s)\n", ch.vm.Hostname, ch.vm.Project, ch.vm.Role)
+		case actionUpdate:
+			fmt.Fprintf(w, "~ update %s\n", ch.vm.Hostname)
+			for _, fc := range vmFieldChanges(ch.old, ch.vm) {
+				fmt.Fprintf(w, "    %s: %q -> %q\n", fc.field, fc.old, fc.new)
+			}
+		case actionDelete:
+			fmt.Fprintf(w, "- delete %s (project=%s role=%s)\n", ch.vm.Hostname, ch.vm.Project, ch.vm.Role)
+		}
+	}
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "No changes, the server matches the manifests.")
+		return
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n", counts[actionCreate], counts[actionUpdate], counts[actionDelete])
+}
+
+// listAllVMs fetches every vm on the server
+func listAllVMs() ([]*pbv2.Virtualmachine, error) {
+	return listVMs("", "")
+}
+
+// listVMs fetches the vms in project with role, empty matches all
+func listVMs(project, role string) ([]*pbv2.Virtualmachine, error) {
+	var vms []*pbv2.Virtualmachine
+	req := &pbv2.ListVirtualmachinesRequest{Project: project, Role: role, PageSize: 500}
+	err := eachVMPage(req, func(page []*pbv2.Virtualmachine) error {
+		vms = append(vms, page...)
+		return nil
+	})
+	return vms, err
+}
+
+// updateRequest changes only the fields of the vm that differ from the
+// manifests, leaving those they do not set as they are
+func updateRequest(ch change) *pbv2.UpdateVirtualmachineRequest {
+	mask := &fieldmaskpb.FieldMask{}
+	for _, fc := range vmFieldChanges(ch.old, ch.vm) {
+		mask.Paths = append(mask.Paths, fc.field)
+	}
+	return &pbv2.UpdateVirtualmachineRequest{Hostname: ch.vm.Hostname, Vm: ch.vm, UpdateMask: mask}
+}
+
+// applyChange makes one change on the server
+func applyChange(ch change) error {
+	var err error
+	switch ch.action {
+	case actionCreate:
+		_, err = c.Create(ctx, &pbv2.CreateVirtualmachineRequest{Vm: ch.vm})
+	case actionUpdate:
+		_, err = c.Update(ctx, updateRequest(ch))
+	case actionDelete:
+		_, err = c.Delete(ctx, &pbv2.DeleteVirtualmachineRequest{Hostname: ch.vm.Hostname})
+	}
+	if err != nil {
+		return callError(err, "could not %s vm %s", ch.action, ch.vm.Hostname)
+	}
+	return nil
+}
+
+// ApplyCommandFunc r
+func ApplyCommandFunc(cmd *cobra.Command, args []string) error {
+	desired, err := readManifests(manifestPaths)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	plan, err := livePlan(desired, prune)
+	if err != nil {
+		return err
+	}
+	writePlan(os.Stdout, plan)
+	if dryRun || len(plan) == 0 {
+		return nil
+	}
+
+	for _, ch := range plan {
+		if err := applyChange(ch); err != nil {
+			return err
+		}
+		fmt.Printf("%sd %s\n", ch.action, ch.vm.Hostname)
+	}
+	return nil
+}
+
+// ApplyCommand r
+func ApplyCommand() *cobra.Command {
+	applycmd := &cobra.Command{
+		Use:   "apply -f <file|dir|->...",
+		Short: "Create, update and with --prune delete vms so the server matches the manifests",
+		Long: `Apply reads vms from YAML or JSON manifests, prints the plan to bring the
+server in line with them and carries it out.
+
+A manifest holds one vm, a list of vms, or a list under "vms" as printed by
+'sreapi vm list -o yaml'. Directories are searched for .yaml, .yml and .json
+files.
+
+A manifest may leave out the vm's addresses, sizing and the labels its role
+defaults, the vm keeps the ones it has. Updates only change the fields that
+differ.
+
+With --prune vms missing from the manifests are deleted, only in the projects
+the manifests have vms in.`,
+		Args: cobra.NoArgs,
+		RunE: ApplyCommandFunc,
+	}
+
+	applycmd.Flags().StringSliceVarP(&manifestPaths, "filename", "f", nil, "manifest file or directory, - for stdin, can be repeated")
+	applycmd.Flags().BoolVar(&prune, "prune", false, "delete vms missing from the manifests, in the projects the manifests cover")
+	applycmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without changing anything")
+	applycmd.MarkFlagRequired("filename")
+	return applycmd
+}
+
+func init() {
+	rootCmd.AddCommand(ApplyCommand())
+}
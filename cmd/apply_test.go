@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestVMFieldChanges(t *testing.T) {
+	old := &pbv2.Virtualmachine{
+		Hostname:  "web01",
+		Project:   "billing",
+		Role:      "web",
+		Labels:    map[string]string{"env": "prod"},
+		Addresses: []string{"10.0.0.5", "10.0.0.6"},
+		Sizing:    &pbv2.Sizing{Cpus: 2},
+	}
+	tests := []struct {
+		name   string
+		change func(vm *pbv2.Virtualmachine)
+		fields string
+	}{
+		{"same", func(vm *pbv2.Virtualmachine) {}, ""},
+		{"address order", func(vm *pbv2.Virtualmachine) { vm.Addresses = []string{"10.0.0.6", "10.0.0.5"} }, ""},
+		{"no sizing keeps the vm's", func(vm *pbv2.Virtualmachine) { vm.Sizing = nil }, ""},
+		{"no addresses keeps the vm's", func(vm *pbv2.Virtualmachine) { vm.Addresses = nil }, ""},
+		{"project and role", func(vm *pbv2.Virtualmachine) { vm.Project, vm.Role = "shop", "db" }, "project,role"},
+		{"labels", func(vm *pbv2.Virtualmachine) { vm.Labels = nil }, "labels"},
+		{"addresses", func(vm *pbv2.Virtualmachine) { vm.Addresses = vm.Addresses[:1] }, "addresses"},
+		{"sizing", func(vm *pbv2.Virtualmachine) { vm.Sizing = &pbv2.Sizing{Cpus: 4} }, "sizing"},
+	}
+	for _, tt := range tests {
+		vm := &pbv2.Virtualmachine{
+			Hostname:  old.Hostname,
+			Project:   old.Project,
+			Role:      old.Role,
+			Labels:    map[string]string{"env": "prod"},
+			Addresses: append([]string(nil), old.Addresses...),
+			Sizing:    &pbv2.Sizing{Cpus: 2},
+		}
+		tt.change(vm)
+		var fields []string
+		for _, fc := range vmFieldChanges(old, vm) {
+			fields = append(fields, fc.field)
+		}
+		if got := strings.Join(fields, ","); got != tt.fields {
+			t.Errorf("%s: vmFieldChanges = %q, want %q", tt.name, got, tt.fields)
+		}
+	}
+}
+
+func TestMakePlan(t *testing.T) {
+	vm := func(hostname, project, role string) *pbv2.Virtualmachine {
+		return &pbv2.Virtualmachine{Hostname: hostname, Project: project, Role: role}
+	}
+	current := []*pbv2.Virtualmachine{
+		vm("web01", "billing", "web"),
+		vm("web02", "billing", "web"),
+		vm("db01", "billing", "db"),
+		vm("shop01", "shop", "web"),
+	}
+	desired := []*pbv2.Virtualmachine{
+		vm("web01", "billing", "web"),
+		vm("web02", "billing", "cache"),
+		vm("web03", "billing", "web"),
+	}
+	tests := []struct {
+		prune bool
+		want  string
+	}{
+		{false, "update web02,create web03"},
+		// shop01 is in a project the manifests do not cover
+		{true, "delete db01,update web02,create web03"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, ch := range makePlan(desired, current, tt.prune) {
+			got = append(got, ch.action+" "+ch.vm.Hostname)
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("makePlan(prune %v) = %s, want %s", tt.prune, strings.Join(got, ","), tt.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	writePlan(&buf, makePlan(desired, current, true))
+	for _, s := range []string{
+		"- delete db01 (project=billing role=db)",
+		"~ update web02\n    role: \"web\" -> \"cache\"",
+		"+ create web03 (project=billing role=web)",
+		"Plan: 1 to create, 1 to update, 1 to delete.",
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("writePlan output does not contain %q:\n%s", s, buf.String())
+		}
+	}
+}
+
+func TestUpdateRequest(t *testing.T) {
+	old := &pbv2.Virtualmachine{
+		Hostname:  "web01",
+		Project:   "billing",
+		Role:      "web",
+		Addresses: []string{"10.0.0.5"},
+		Sizing:    &pbv2.Sizing{Cpus: 2},
+	}
+	tests := []struct {
+		name string
+		vm   *pbv2.Virtualmachine
+		mask string
+	}{
+		{"role", &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "db"}, "role"},
+		{"labels and addresses", &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web",
+			Labels: map[string]string{"env": "prod"}, Addresses: []string{"10.0.0.6"}}, "labels,addresses"},
+		{"sizing", &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web", Sizing: &pbv2.Sizing{Cpus: 4}}, "sizing"},
+	}
+	for _, tt := range tests {
+		req := updateRequest(change{action: actionUpdate, vm: tt.vm, old: old})
+		if got := strings.Join(req.UpdateMask.GetPaths(), ","); got != tt.mask || req.Hostname != "web01" || req.Vm != tt.vm {
+			t.Errorf("%s: updateRequest = %s with mask %q, want web01 with mask %q", tt.name, req.Hostname, got, tt.mask)
+		}
+	}
+}
+
+func TestKeepRoleLabels(t *testing.T) {
+	current := []*pbv2.Virtualmachine{
+		{Hostname: "web01", Project: "billing", Role: "web", Labels: map[string]string{"env": "prod", "tier": "front", "team": "pay"}},
+		{Hostname: "web02", Project: "billing", Role: "web", Labels: map[string]string{"team": "pay"}},
+		{Hostname: "db01", Project: "billing", Role: "db", Labels: map[string]string{"env": "prod"}},
+	}
+	roles := []*pbv2.Role{
+		{Name: "web", DefaultLabels: map[string]string{"env": "prod", "tier": "front"}},
+		{Name: "db"},
+	}
+	tests := []struct {
+		name   string
+		vm     *pbv2.Virtualmachine
+		labels string
+		plan   string
+	}{
+		{"defaults left out", &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web", Labels: map[string]string{"team": "pay"}},
+			"env=prod,team=pay,tier=front", ""},
+		{"default overridden", &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web", Labels: map[string]string{"team": "pay", "env": "dev"}},
+			"env=dev,team=pay,tier=front", "update web01"},
+		{"defaults the vm does not have", &pbv2.Virtualmachine{Hostname: "web02", Project: "billing", Role: "web", Labels: map[string]string{"team": "pay"}},
+			"team=pay", ""},
+		{"labels not defaulted by the role", &pbv2.Virtualmachine{Hostname: "db01", Project: "billing", Role: "db"},
+			"", "update db01"},
+		{"new vm", &pbv2.Virtualmachine{Hostname: "web03", Project: "billing", Role: "web"},
+			"", "create web03"},
+	}
+	for _, tt := range tests {
+		keepRoleLabels([]*pbv2.Virtualmachine{tt.vm}, current, roles)
+		if got := formatLabels(tt.vm.Labels, ","); got != tt.labels {
+			t.Errorf("%s: labels = %q, want %q", tt.name, got, tt.labels)
+		}
+		var plan []string
+		for _, ch := range makePlan([]*pbv2.Virtualmachine{tt.vm}, current, false) {
+			plan = append(plan, ch.action+" "+ch.vm.Hostname)
+		}
+		if got := strings.Join(plan, ","); got != tt.plan {
+			t.Errorf("%s: plan = %q, want %q", tt.name, got, tt.plan)
+		}
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifest  string
+		hostnames string
+		err       string
+	}{
+		{"one vm", "hostname: web01\nproject: billing\nrole: web\n", "web01", ""},
+		{"documents", "hostname: web01\nproject: billing\nrole: web\n---\n---\nhostname: web02\nproject: billing\nrole: web\n", "web01,web02", ""},
+		{"list", "- hostname: web01\n  project: billing\n  role: web\n- hostname: web02\n  project: billing\n  role: web\n", "web01,web02", ""},
+		{"list output", `{"vms": [{"hostname": "web01", "project": "billing", "role": "web"}], "next_page_token": ""}`, "web01", ""},
+		{"labels and sizing", "hostname: web01\nproject: billing\nrole: web\nlabels:\n  env: prod\nsizing:\n  cpus: 2\n", "web01", ""},
+		{"unknown field", "hostname: web01\nproject: billing\nrole: web\ncolour: blue\n", "", "document 1"},
+		{"no hostname", "project: billing\nrole: web\n", "", "without a hostname"},
+		{"no project", "hostname: web01\nrole: web\n", "", "has no project"},
+		{"no role", "hostname: web01\nproject: billing\n", "", "has no role"},
+		{"scalar", "web01\n", "", "want a vm"},
+	}
+	for _, tt := range tests {
+		vms, err := parseManifest([]byte(tt.manifest))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: parseManifest error = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: parseManifest error = %v", tt.name, err)
+			continue
+		}
+		var hostnames []string
+		for _, vm := range vms {
+			hostnames = append(hostnames, vm.Hostname)
+		}
+		if strings.Join(hostnames, ",") != tt.hostnames {
+			t.Errorf("%s: parseManifest = %s, want %s", tt.name, strings.Join(hostnames, ","), tt.hostnames)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var color string
+
+// ANSI colours for diff lines
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+	colorBold  = "\x1b[1m"
+	colorReset = "\x1b[0m"
+)
+
+// driftError says the server does not match the manifests
+type driftError struct {
+	vms int
+}
+
+func (e *driftError) Error() string {
+	return fmt.Sprintf("%d vms differ from the manifests", e.vms)
+}
+
+// vmLines is vm as YAML, one field per line, so every field is diffed
+func vmLines(vm *pbv2.Virtualmachine) ([]string, error) {
+	if vm == nil {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	m := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	if err := m.Marshal(&buf, vm); err != nil {
+		return nil, err
+	}
+	y, err := yaml.JSONToYAML(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(y), "\n"), "\n"), nil
+}
+
+// diffLines compares a and b line by line, returning every line prefixed
+// with " ", "-" or "+"
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+"+b[j])
+	}
+	return out
+}
+
+// updatedVM is how the vm of an update change ends up, the fields the
+// manifests leave out keep their live values
+func updatedVM(ch change) *pbv2.Virtualmachine {
+	vm := proto.Clone(ch.old).(*pbv2.Virtualmachine)
+	for _, fc := range vmFieldChanges(ch.old, ch.vm) {
+		switch fc.field {
+		case "project":
+			vm.Project = ch.vm.Project
+		case "role":
+			vm.Role = ch.vm.Role
+		case "labels":
+			vm.Labels = ch.vm.Labels
+		case "addresses":
+			vm.Addresses = ch.vm.Addresses
+		case "sizing":
+			vm.Sizing = ch.vm.Sizing
+		}
+	}
+	return vm
+}
+
+// writeDiff prints plan as a unified diff from the server to the manifests,
+// a hunk per vm
+func writeDiff(w io.Writer, plan []change, colored bool) error {
+	paint := func(c, s string) string {
+		if !colored {
+			return s
+		}
+		return c + s + colorReset
+	}
+
+	if len(plan) > 0 {
+		fmt.Fprintln(w, paint(colorBold, "--- live"))
+		fmt.Fprintln(w, paint(colorBold, "+++ manifests"))
+	}
+	for _, ch := range plan {
+		var old, vm []string
+		var err error
+		if old, err = vmLines(ch.old); err != nil {
+			return err
+		}
+		switch ch.action {
+		case actionCreate:
+			vm, err = vmLines(ch.vm)
+		case actionUpdate:
+			vm, err = vmLines(updatedVM(ch))
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, paint(colorCyan, fmt.Sprintf("@@ %s %s @@", ch.action, ch.vm.Hostname)))
+		for _, l := range diffLines(old, vm) {
+			switch l[0] {
+			case '-':
+				l = paint(colorRed, l)
+			case '+':
+				l = paint(colorGreen, l)
+			}
+			fmt.Fprintln(w, l)
+		}
+	}
+	return nil
+}
+
+// useColor reads --color, auto colours terminals unless NO_COLOR is set
+func useColor(f *os.File) (bool, error) {
+	switch color {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, usageErrorf("unknown --color %q, want auto, always or never", color)
+}
+
+// DiffCommandFunc r
+func DiffCommandFunc(cmd *cobra.Command, args []string) error {
+	colored, err := useColor(os.Stdout)
+	if err != nil {
+		return err
+	}
+	desired, err := readManifests(manifestPaths)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	plan, err := livePlan(desired, true)
+	if err != nil {
+		return err
+	}
+	if err := writeDiff(os.Stdout, plan, colored); err != nil {
+		return err
+	}
+	if len(plan) > 0 {
+		return &driftError{vms: len(plan)}
+	}
+	return nil
+}
+
+// DiffCommand r
+func DiffCommand() *cobra.Command {
+	diffcmd := &cobra.Command{
+		Use:   "diff -f <file|dir|->...",
+		Short: "Show how the live vms differ from the manifests",
+		Long: `Diff prints a unified diff from the live vms to the manifests, which are read
+as by apply. Vms missing from the manifests are shown as removed, in the
+projects the manifests have vms in.
+
+Diff exits 8 when the server does not match the manifests, so it can be run
+as a drift check.`,
+		Args: cobra.NoArgs,
+		RunE: DiffCommandFunc,
+	}
+
+	diffcmd.Flags().StringSliceVarP(&manifestPaths, "filename", "f", nil, "manifest file or directory, - for stdin, can be repeated")
+	diffcmd.Flags().StringVar(&color, "color", "auto", "colour the diff: auto, always or never")
+	diffcmd.MarkFlagRequired("filename")
+	return diffcmd
+}
+
+func init() {
+	rootCmd.AddCommand(DiffCommand())
+}
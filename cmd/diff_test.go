@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", ""},
+		{"a b c", "a b c", " a, b, c"},
+		{"", "a b", "+a,+b"},
+		{"a b", "", "-a,-b"},
+		{"a b c", "a x c", " a,-b,+x, c"},
+		{"a b c d", "a c d e", " a,-b, c, d,+e"},
+		{"x a b", "a b y", "-x, a, b,+y"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(diffLines(strings.Fields(tt.a), strings.Fields(tt.b)), ",")
+		if got != tt.want {
+			t.Errorf("diffLines(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	old := &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web"}
+	plan := []change{
+		{action: actionUpdate, vm: &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "db"}, old: old},
+		{action: actionDelete, vm: old, old: old},
+	}
+	tests := []struct {
+		colored bool
+		want    []string
+	}{
+		{false, []string{
+			"--- live\n+++ manifests\n@@ update web01 @@\n",
+			"\n-role: web\n+role: db\n",
+			"@@ delete web01 @@\n",
+			"\n-project: billing\n",
+		}},
+		{true, []string{
+			colorCyan + "@@ update web01 @@" + colorReset,
+			colorRed + "-role: web" + colorReset,
+			colorGreen + "+role: db" + colorReset,
+		}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeDiff(&buf, plan, tt.colored); err != nil {
+			t.Fatalf("writeDiff: %v", err)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("writeDiff(colored %v) output does not contain %q:\n%s", tt.colored, s, buf.String())
+			}
+		}
+	}
+
+	// Fields the manifests leave out are not shown as removed
+	live := &pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web", Addresses: []string{"10.0.0.5"}, Sizing: &pbv2.Sizing{Cpus: 2}}
+	var kept bytes.Buffer
+	if err := writeDiff(&kept, []change{{action: actionUpdate, vm: plan[0].vm, old: live}}, false); err != nil {
+		t.Fatalf("writeDiff: %v", err)
+	}
+	if strings.Contains(kept.String(), "\n-addresses") || strings.Contains(kept.String(), "\n-  cpus") {
+		t.Errorf("writeDiff shows kept fields as removed:\n%s", kept.String())
+	}
+
+	var buf bytes.Buffer
+	if err := writeDiff(&buf, nil, false); err != nil || buf.Len() != 0 {
+		t.Errorf("writeDiff of an empty plan = %q, %v, want nothing", buf.String(), err)
+	}
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/achanno/sreapi/inventory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	zoneName   string
+	zoneDomain string
+	zoneNS     []string
+	zoneMbox   string
+	zoneSerial uint32
+	zoneTTL    uint32
+	zoneFile   string
+)
+
+// DNSExportCommandFunc r
+func DNSExportCommandFunc(cmd *cobra.Command, args []string) error {
+	z := inventory.Zone{
+		Name:   zoneName,
+		Domain: zoneDomain,
+		NS:     zoneNS,
+		Mbox:   zoneMbox,
+		Serial: zoneSerial,
+		TTL:    zoneTTL,
+	}
+	if inventory.IsReverseZone(z.Name) && z.Domain == "" {
+		return usageErrorf("--domain is required for reverse zones")
+	}
+	if z.Serial == 0 {
+		z.Serial = uint32(time.Now().Unix())
+	}
+
+	vms, err := listAllVMs()
+	if err != nil {
+		return err
+	}
+	if zoneFile == "" {
+		return z.Write(os.Stdout, vms)
+	}
+	if err := writeFileAtomic(zoneFile, 0o644, func(w io.Writer) error { return z.Write(w, vms) }); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d records to %s\n", len(z.Records(vms)), zoneFile)
+	return nil
+}
+
+// DNSExportCommand r
+func DNSExportCommand() *cobra.Command {
+	exportcmd := &cobra.Command{
+		Use:   "export --zone <zone> [--file <path>]",
+		Short: "Write the vms' addresses as a BIND zone file",
+		Long: `Export writes a BIND zone file with the addresses of the vms.
+
+For a forward zone like example.internal each vm gets A and AAAA records,
+named by its hostname in the zone. For a reverse zone like 10.in-addr.arpa
+or 8.b.d.0.1.0.0.2.ip6.arpa the addresses within it get PTR records pointing
+at the hostnames in --domain.
+
+The serial is the current unix time unless --serial is given.`,
+		Args: cobra.NoArgs,
+		RunE: DNSExportCommandFunc,
+	}
+
+	exportcmd.Flags().StringVar(&zoneName, "zone", "", "zone to write, e.g. example.internal or 10.in-addr.arpa")
+	exportcmd.Flags().StringVar(&zoneDomain, "domain", "", "domain PTR records point into, required for reverse zones, the zone for forward zones")
+	exportcmd.Flags().StringSliceVar(&zoneNS, "ns", nil, "name servers of the zone, ns1.<domain> by default")
+	exportcmd.Flags().StringVar(&zoneMbox, "mbox", "", "SOA contact, hostmaster.<domain> by default")
+	exportcmd.Flags().Uint32Var(&zoneSerial, "serial", 0, "SOA serial, the current unix time by default")
+	exportcmd.Flags().Uint32Var(&zoneTTL, "ttl", inventory.DefaultTTL, "ttl of the records")
+	exportcmd.Flags().StringVarP(&zoneFile, "file", "f", "", "zone file to write, stdout when not set")
+	exportcmd.MarkFlagRequired("zone")
+	return exportcmd
+}
+
+// DNSCommand r
+func DNSCommand() *cobra.Command {
+	dnscmd := &cobra.Command{
+		Use:   "dns",
+		Short: "Publish the vms' hostnames in dns",
+		Long: `Dns exports the vms as zone files. The server can also answer A, AAAA and
+PTR queries itself, see 'sreapi vm server --dns-addr'.`,
+	}
+
+	dnscmd.AddCommand(DNSExportCommand())
+	return dnscmd
+}
+
+func init() {
+	rootCmd.AddCommand(DNSCommand())
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/achanno/sreapi/inventory"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+var encMapping string
+
+// ENCCommandFunc r
+func ENCCommandFunc(cmd *cobra.Command, args []string) error {
+	path := encMapping
+	if path == "" {
+		path = viper.GetString("enc.mapping")
+	}
+	if path == "" {
+		return usageErrorf("--mapping or enc.mapping in the config file is required")
+	}
+	m, err := inventory.LoadENCMapping(path)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	hostname := args[0]
+	vm, err := c.Get(ctx, &pbv2.GetVirtualmachineRequest{Hostname: hostname})
+	if short, _, ok := strings.Cut(hostname, "."); ok && status.Code(err) == codes.NotFound {
+		vm, err = c.Get(ctx, &pbv2.GetVirtualmachineRequest{Hostname: short})
+	}
+	if err != nil {
+		return callError(err, "could not get vm %s", hostname)
+	}
+
+	node := m.Classify(vm)
+	role, err := roles.GetRole(ctx, &pbv2.GetRoleRequest{Name: vm.Role})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return callError(err, "could not get role %s", vm.Role)
+	}
+	if role != nil {
+		node.AddClasses(role.Classes...)
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// ENCCommand r
+func ENCCommand() *cobra.Command {
+	enccmd := &cobra.Command{
+		Use:   "enc <hostname>",
+		Short: "Classify a vm for puppet or salt as an external node classifier",
+		Long: `Enc prints the classes, parameters and environment of a vm as YAML, as
+puppet and salt expect from an external node classifier. A fully qualified
+hostname that is not a vm falls back to the vm named by its first label, as
+puppet passes certnames.
+
+The mapping file says what every vm, each project, each role and each label
+(by name=value, or by name for any value) adds, in that order. Later classes
+are added, later parameters and environments win. The classes of the vm's
+role in the roles catalog are added last:
+
+  environment: production
+  classes: [base]
+  projects:
+    billing:
+      parameters: {team: payments}
+  roles:
+    web:
+      classes: [nginx]
+  labels:
+    env=staging:
+      environment: staging
+
+The parameters always include sreapi_hostname, sreapi_project, sreapi_role,
+sreapi_labels and sreapi_addresses. The server serves the same document at
+/enc/<hostname> when started with --enc-mapping.`,
+		Args: cobra.ExactArgs(1),
+		RunE: ENCCommandFunc,
+	}
+
+	enccmd.Flags().StringVar(&encMapping, "mapping", "", "mapping file, enc.mapping in the config file by default")
+	return enccmd
+}
+
+func init() {
+	rootCmd.AddCommand(ENCCommand())
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes, scripts can tell failures apart by them
+const (
+	exitFailure          = 1
+	exitUsage            = 2
+	exitNotFound         = 3
+	exitAlreadyExists    = 4
+	exitPermissionDenied = 5
+	exitInvalid          = 6
+	exitUnavailable      = 7
+	exitDrift            = 8
+)
+
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  any other failure
+  2  bad flags or arguments
+  3  not found
+  4  already exists
+  5  permission denied or not authenticated
+  6  rejected as invalid by the server
+  7  server unreachable or timed out
+  8  diff found the server does not match the manifests`
+
+// usageError is a mistake in the command line
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func usageErrorf(format string, a ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, a...)}
+}
+
+// rpcError is a failed call: what the cli was doing and the status the
+// server returned
+type rpcError struct {
+	action string
+	st     *status.Status
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.action, e.st.Message(), e.st.Code())
+}
+
+// callError wraps an error returned by a call, action says what was being
+// done, e.g. "could not get vm web01"
+func callError(err error, format string, a ...interface{}) error {
+	return &rpcError{action: fmt.Sprintf(format, a...), st: status.Convert(err)}
+}
+
+// exitCode picks the exit code for err
+func exitCode(err error) int {
+	var ue *usageError
+	if errors.As(err, &ue) {
+		return exitUsage
+	}
+	var de *driftError
+	if errors.As(err, &de) {
+		return exitDrift
+	}
+	var re *rpcError
+	if !errors.As(err, &re) {
+		return exitFailure
+	}
+	switch re.st.Code() {
+	case codes.NotFound:
+		return exitNotFound
+	case codes.AlreadyExists:
+		return exitAlreadyExists
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return exitPermissionDenied
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return exitInvalid
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return exitUnavailable
+	}
+	return exitFailure
+}
+
+// reportError writes err to w, with any details the server attached to its
+// status, one per line
+func reportError(w io.Writer, err error) {
+	fmt.Fprintln(w, "Error:", err)
+
+	var re *rpcError
+	if !errors.As(err, &re) {
+		return
+	}
+	for _, d := range re.st.Details() {
+		for _, line := range detailLines(d) {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+	switch re.st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		fmt.Fprintf(w, "  is the server running on %s?\n", serverAddr)
+	}
+}
+
+// detailLines renders a status detail for people
+func detailLines(d interface{}) []string {
+	var lines []string
+	switch d := d.(type) {
+	case *errdetails.BadRequest:
+		for _, v := range d.FieldViolations {
+			lines = append(lines, fmt.Sprintf("%s: %s", v.Field, v.Description))
+		}
+	case *errdetails.PreconditionFailure:
+		for _, v := range d.Violations {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", v.Type, v.Subject, v.Description))
+		}
+	case *errdetails.QuotaFailure:
+		for _, v := range d.Violations {
+			lines = append(lines, fmt.Sprintf("quota %s: %s", v.Subject, v.Description))
+		}
+	case *errdetails.ResourceInfo:
+		line := fmt.Sprintf("%s %s", d.ResourceType, d.ResourceName)
+		if d.Description != "" {
+			line += ": " + d.Description
+		}
+		lines = append(lines, line)
+	case *errdetails.RetryInfo:
+		lines = append(lines, fmt.Sprintf("retry after %s", d.RetryDelay.AsDuration().Round(time.Millisecond)))
+	case *errdetails.ErrorInfo:
+		lines = append(lines, fmt.Sprintf("reason %s from %s", d.Reason, d.Domain))
+	case *errdetails.Help:
+		for _, l := range d.Links {
+			lines = append(lines, fmt.Sprintf("see %s: %s", l.Description, l.Url))
+		}
+	case *errdetails.LocalizedMessage:
+		lines = append(lines, d.Message)
+	case *errdetails.DebugInfo:
+		if d.Detail != "" {
+			lines = append(lines, d.Detail)
+		}
+		if len(d.StackEntries) > 0 {
+			lines = append(lines, strings.Join(d.StackEntries, "\n  "))
+		}
+	case error:
+		// Details the cli has no types for
+		lines = append(lines, d.Error())
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestExitCode(t *testing.T) {
+	rpc := func(code codes.Code) error {
+		return callError(status.Error(code, "failed"), "could not do it")
+	}
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("boom"), exitFailure},
+		{usageErrorf("bad flag"), exitUsage},
+		{fmt.Errorf("wrapped: %w", usageErrorf("bad flag")), exitUsage},
+		{&driftError{vms: 2}, exitDrift},
+		{rpc(codes.NotFound), exitNotFound},
+		{rpc(codes.AlreadyExists), exitAlreadyExists},
+		{rpc(codes.PermissionDenied), exitPermissionDenied},
+		{rpc(codes.Unauthenticated), exitPermissionDenied},
+		{rpc(codes.InvalidArgument), exitInvalid},
+		{rpc(codes.FailedPrecondition), exitInvalid},
+		{rpc(codes.OutOfRange), exitInvalid},
+		{rpc(codes.Unavailable), exitUnavailable},
+		{rpc(codes.DeadlineExceeded), exitUnavailable},
+		{rpc(codes.Internal), exitFailure},
+		{rpc(codes.ResourceExhausted), exitFailure},
+		{callError(errors.New("not a status"), "could not do it"), exitFailure},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestReportError(t *testing.T) {
+	st := status.New(codes.InvalidArgument, "bad vm")
+	st, err := st.WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "vm.hostname", Description: "is required"}}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	reportError(&buf, callError(st.Err(), "could not create vm %s", "web01"))
+	want := `Error: could not create vm web01: bad vm (InvalidArgument)
+  vm.hostname: is required
+  retry after 1.5s
+`
+	if buf.String() != want {
+		t.Errorf("reportError wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	serverAddr = "sreapi.example:5555"
+	buf.Reset()
+	reportError(&buf, callError(status.Error(codes.Unavailable, "connection refused"), "could not list vms"))
+	if !strings.Contains(buf.String(), "is the server running on sreapi.example:5555?") {
+		t.Errorf("reportError for an unreachable server wrote\n%s", buf.String())
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// HealthCommandFunc r
+func HealthCommandFunc(cmd *cobra.Command, args []string) error {
+	var service string
+	if len(args) > 0 {
+		service = args[0]
+	}
+
+	r, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return callError(err, "could not check health")
+	}
+	fmt.Println("Status:", r.Status)
+	if r.Status != healthpb.HealthCheckResponse_SERVING {
+		return callError(status.Errorf(codes.Unavailable, "server is %s", r.Status), "not healthy")
+	}
+	return nil
+}
+
+// HealthCommand r
+func HealthCommand() *cobra.Command {
+	healthcmd := &cobra.Command{
+		Use:   "health [service]",
+		Short: "Check server health, exits non-zero when not serving",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  HealthCommandFunc,
+	}
+	return healthcmd
+}
+
+func init() {
+	rootCmd.AddCommand(HealthCommand())
+}
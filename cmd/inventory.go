@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/achanno/sreapi/inventory"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ansibleList bool
+	ansibleHost string
+	scrapePort  int
+	sdFile      string
+)
+
+// writeJSON prints v indented, as the inventory documents are read by people
+// as well as tools
+func writeJSON(v interface{}) error {
+	return encodeJSON(os.Stdout, v)
+}
+
+func encodeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeFileAtomic replaces path with what write writes. The file is written
+// next to path and renamed over it, so a reader watching path never sees half
+// of it. It keeps the mode of the file it replaces, new files get perm.
+func writeFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// AnsibleInventoryCommandFunc r
+func AnsibleInventoryCommandFunc(cmd *cobra.Command, args []string) error {
+	if ansibleHost != "" {
+		r, err := c.Get(ctx, &pbv2.GetVirtualmachineRequest{Hostname: ansibleHost})
+		if err != nil {
+			return callError(err, "could not get vm %s", ansibleHost)
+		}
+		return writeJSON(inventory.HostVars(r))
+	}
+
+	vms, err := listAllVMs()
+	if err != nil {
+		return err
+	}
+	return writeJSON(inventory.Ansible(vms))
+}
+
+// AnsibleInventoryCommand r
+func AnsibleInventoryCommand() *cobra.Command {
+	ansiblecmd := &cobra.Command{
+		Use:   "ansible --list | --host <hostname>",
+		Short: "Print the vms as an ansible dynamic inventory",
+		Long: `Ansible prints the vms as an ansible inventory script does, so a script
+running 'sreapi inventory ansible "$@"' can be used as an inventory.
+
+With --list every vm is printed, in project_<project>, role_<role> and
+label_<name>_<value> groups with sreapi_hostname, sreapi_project,
+sreapi_role and sreapi_labels as hostvars. With --host only the hostvars of
+one vm are printed.
+
+The server serves the same documents at /inventory/ansible and
+/inventory/ansible?host=<hostname>.`,
+		Args: cobra.NoArgs,
+		RunE: AnsibleInventoryCommandFunc,
+	}
+
+	ansiblecmd.Flags().BoolVar(&ansibleList, "list", false, "print every vm with its groups and hostvars")
+	ansiblecmd.Flags().StringVar(&ansibleHost, "host", "", "print the hostvars of one vm")
+	ansiblecmd.MarkFlagsMutuallyExclusive("list", "host")
+	ansiblecmd.MarkFlagsOneRequired("list", "host")
+	return ansiblecmd
+}
+
+// PrometheusInventoryCommandFunc r
+func PrometheusInventoryCommandFunc(cmd *cobra.Command, args []string) error {
+	if scrapePort < 1 || scrapePort > 65535 {
+		return usageErrorf("--port must be from 1 to 65535")
+	}
+	vms, err := listVMs(project, role)
+	if err != nil {
+		return err
+	}
+
+	groups := inventory.PrometheusTargets(vms, scrapePort)
+	if sdFile == "" {
+		return writeJSON(groups)
+	}
+	err = writeFileAtomic(sdFile, 0o644, func(w io.Writer) error {
+		return encodeJSON(w, groups)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d targets to %s\n", len(groups), sdFile)
+	return nil
+}
+
+// PrometheusInventoryCommand r
+func PrometheusInventoryCommand() *cobra.Command {
+	promcmd := &cobra.Command{
+		Use:   "prometheus [--project <project>] [--role <role>] [--file <path>]",
+		Short: "Write the vms as prometheus file_sd targets",
+		Long: `Prometheus prints the vms as a prometheus file_sd target list, or with
+--file replaces that file, which prometheus picks up when it changes.
+
+Each vm is a target at <hostname>:<port> with hostname, project and role
+target labels, and label_<name> for each of its labels.
+
+The server serves the same targets for http_sd at
+/sd/prometheus?project=<project>&role=<role>&port=<port>.`,
+		Args: cobra.NoArgs,
+		RunE: PrometheusInventoryCommandFunc,
+	}
+
+	promcmd.Flags().StringVar(&project, "project", "", "only vms in this project")
+	promcmd.Flags().StringVar(&role, "role", "", "only vms with this role")
+	promcmd.Flags().IntVar(&scrapePort, "port", inventory.DefaultScrapePort, "port to scrape the vms on")
+	promcmd.Flags().StringVarP(&sdFile, "file", "f", "", "file_sd file to write, stdout when not set")
+	return promcmd
+}
+
+// InventoryCommand r
+func InventoryCommand() *cobra.Command {
+	inventorycmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export the vms to configuration management and monitoring tools",
+	}
+
+	inventorycmd.AddCommand(AnsibleInventoryCommand())
+	inventorycmd.AddCommand(PrometheusInventoryCommand())
+	return inventorycmd
+}
+
+func init() {
+	rootCmd.AddCommand(InventoryCommand())
+}
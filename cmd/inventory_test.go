@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "targets.json")
+	write := func(s string) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := io.WriteString(w, s)
+			return err
+		}
+	}
+
+	if err := writeFileAtomic(path, 0o640, write("one")); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Errorf("new file mode = %v, want 0640", info.Mode().Perm())
+	}
+
+	// the file keeps its mode when it is replaced
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, 0o644, write("two")); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("replaced file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// a failed write leaves the file as it was and no temporary file behind
+	failed := errors.New("failed")
+	err := writeFileAtomic(path, 0o644, func(w io.Writer) error {
+		io.WriteString(w, "half")
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("writeFileAtomic error = %v, want %v", err, failed)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "two" {
+		t.Errorf("file = %q, want two", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("got %d files in %s, want 1", len(entries), dir)
+	}
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+)
+
+var (
+	subnetDescription string
+	subnetGateway     string
+	subnetPools       []string
+	ipamSubnet        string
+	ipamAddress       string
+)
+
+// parsePool parses START-END, e.g. 10.0.0.10-10.0.0.99
+func parsePool(s string) (*pbv2.Pool, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok || start == "" || end == "" {
+		return nil, fmt.Errorf("pool %q is not START-END", s)
+	}
+	return &pbv2.Pool{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}, nil
+}
+
+func formatPools(pools []*pbv2.Pool) string {
+	s := make([]string, len(pools))
+	for i, p := range pools {
+		s[i] = p.Start + "-" + p.End
+	}
+	return strings.Join(s, ",")
+}
+
+// tabular reports whether p writes tables, which each command lays out itself
+func (p *printer) tabular() bool {
+	return p.format == "" || p.format == "table" || p.format == "wide"
+}
+
+func writeSubnets(w io.Writer, subnets []*pbv2.Subnet) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "CIDR\tGATEWAY\tPOOLS\tDESCRIPTION")
+	for _, sn := range subnets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sn.Cidr, sn.Gateway, formatPools(sn.Pools), sn.Description)
+	}
+	return tw.Flush()
+}
+
+// IpamSubnetCreateCommandFunc r
+func IpamSubnetCreateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	sn := &pbv2.Subnet{Cidr: args[0], Description: subnetDescription, Gateway: subnetGateway}
+	for _, s := range subnetPools {
+		pool, err := parsePool(s)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		sn.Pools = append(sn.Pools, pool)
+	}
+	sn, err = ipam.CreateSubnet(ctx, &pbv2.CreateSubnetRequest{Subnet: sn})
+	if err != nil {
+		return callError(err, "could not create subnet %s", args[0])
+	}
+	if p.tabular() {
+		return writeSubnets(os.Stdout, []*pbv2.Subnet{sn})
+	}
+	return p.print(sn)
+}
+
+// IpamSubnetListCommandFunc r
+func IpamSubnetListCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := ipam.ListSubnets(ctx, &pbv2.ListSubnetsRequest{})
+	if err != nil {
+		return callError(err, "could not list subnets")
+	}
+	if p.tabular() {
+		return writeSubnets(os.Stdout, r.Subnets)
+	}
+	return p.print(r)
+}
+
+// IpamSubnetDeleteCommandFunc r
+func IpamSubnetDeleteCommandFunc(cmd *cobra.Command, args []string) error {
+	if _, err := ipam.DeleteSubnet(ctx, &pbv2.DeleteSubnetRequest{Cidr: args[0]}); err != nil {
+		return callError(err, "could not delete subnet %s", args[0])
+	}
+	fmt.Println("Deleted:", args[0])
+	return nil
+}
+
+// IpamShowCommandFunc r
+func IpamShowCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	u, err := ipam.GetSubnetUsage(ctx, &pbv2.GetSubnetUsageRequest{Cidr: args[0]})
+	if err != nil {
+		return callError(err, "could not get usage of subnet %s", args[0])
+	}
+	if !p.tabular() {
+		return p.print(u)
+	}
+
+	fmt.Printf("Subnet:      %s\n", u.Subnet.Cidr)
+	if u.Subnet.Description != "" {
+		fmt.Printf("Description: %s\n", u.Subnet.Description)
+	}
+	if u.Subnet.Gateway != "" {
+		fmt.Printf("Gateway:     %s\n", u.Subnet.Gateway)
+	}
+	if len(u.Subnet.Pools) > 0 {
+		fmt.Printf("Pools:       %s\n", formatPools(u.Subnet.Pools))
+	}
+	fmt.Printf("Allocated:   %d of %d, %d free\n\n", u.Allocated, u.Size, u.Free)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tHOSTNAME")
+	for _, a := range u.Allocations {
+		fmt.Fprintf(tw, "%s\t%s\n", a.Address, a.Hostname)
+	}
+	return tw.Flush()
+}
+
+// IpamAllocateCommandFunc r
+func IpamAllocateCommandFunc(cmd *cobra.Command, args []string) error {
+	a, err := ipam.AllocateIP(ctx, &pbv2.AllocateIPRequest{Hostname: args[0], Subnet: ipamSubnet, Address: ipamAddress})
+	if err != nil {
+		return callError(err, "could not allocate an address for vm %s", args[0])
+	}
+	fmt.Println(a.Address)
+	return nil
+}
+
+// IpamReleaseCommandFunc r
+func IpamReleaseCommandFunc(cmd *cobra.Command, args []string) error {
+	if _, err := ipam.ReleaseIP(ctx, &pbv2.ReleaseIPRequest{Hostname: args[0], Address: args[1]}); err != nil {
+		return callError(err, "could not release %s from vm %s", args[1], args[0])
+	}
+	fmt.Println("Released:", args[1])
+	return nil
+}
+
+// IpamSubnetCreateCommand r
+func IpamSubnetCreateCommand() *cobra.Command {
+	createcmd := &cobra.Command{
+		Use:   "create <cidr> [--gateway <address>] [--pool <start>-<end>]",
+		Short: "Create a subnet to allocate addresses from",
+		Long: `Create adds a subnet, which may not overlap an existing one.
+
+Addresses are allocated from its pools, or from the whole subnet without the
+network and IPv4 broadcast addresses when it has none. The gateway is never
+allocated.`,
+		Args: cobra.ExactArgs(1),
+		RunE: IpamSubnetCreateCommandFunc,
+	}
+	createcmd.Flags().StringVar(&subnetDescription, "description", "", "what the subnet is for")
+	createcmd.Flags().StringVar(&subnetGateway, "gateway", "", "gateway address of the subnet")
+	createcmd.Flags().StringSliceVar(&subnetPools, "pool", nil, "range to allocate from as START-END, can be repeated")
+	addOutputFlag(createcmd)
+	return createcmd
+}
+
+// IpamSubnetListCommand r
+func IpamSubnetListCommand() *cobra.Command {
+	listcmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the subnets",
+		Args:  cobra.NoArgs,
+		RunE:  IpamSubnetListCommandFunc,
+	}
+	addOutputFlag(listcmd)
+	return listcmd
+}
+
+// IpamSubnetDeleteCommand r
+func IpamSubnetDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <cidr>",
+		Short: "Delete a subnet with no addresses in use",
+		Args:  cobra.ExactArgs(1),
+		RunE:  IpamSubnetDeleteCommandFunc,
+	}
+}
+
+// IpamSubnetCommand r
+func IpamSubnetCommand() *cobra.Command {
+	subnetcmd := &cobra.Command{
+		Use:   "subnet",
+		Short: "Manage subnets",
+	}
+	subnetcmd.AddCommand(IpamSubnetCreateCommand())
+	subnetcmd.AddCommand(IpamSubnetListCommand())
+	subnetcmd.AddCommand(IpamSubnetDeleteCommand())
+	return subnetcmd
+}
+
+// IpamShowCommand r
+func IpamShowCommand() *cobra.Command {
+	showcmd := &cobra.Command{
+		Use:   "show <cidr>",
+		Short: "Show a subnet's usage and which vms have its addresses",
+		Args:  cobra.ExactArgs(1),
+		RunE:  IpamShowCommandFunc,
+	}
+	addOutputFlag(showcmd)
+	return showcmd
+}
+
+// IpamAllocateCommand r
+func IpamAllocateCommand() *cobra.Command {
+	allocatecmd := &cobra.Command{
+		Use:   "allocate <hostname> --subnet <cidr> [--address <address>]",
+		Short: "Give a vm an address from a subnet",
+		Long: `Allocate gives the vm the next free address in --subnet, or --address when
+it is set, and prints it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: IpamAllocateCommandFunc,
+	}
+	allocatecmd.Flags().StringVar(&ipamSubnet, "subnet", "", "subnet to allocate from")
+	allocatecmd.Flags().StringVar(&ipamAddress, "address", "", "address to allocate, the next free one when not set")
+	allocatecmd.MarkFlagRequired("subnet")
+	return allocatecmd
+}
+
+// IpamReleaseCommand r
+func IpamReleaseCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "release <hostname> <address>",
+		Short: "Take an address from a vm",
+		Args:  cobra.ExactArgs(2),
+		RunE:  IpamReleaseCommandFunc,
+	}
+}
+
+// IpamCommand r
+func IpamCommand() *cobra.Command {
+	ipamcmd := &cobra.Command{
+		Use:   "ipam",
+		Short: "Manage subnets and the vms' addresses in them",
+	}
+
+	ipamcmd.AddCommand(IpamSubnetCommand())
+	ipamcmd.AddCommand(IpamShowCommand())
+	ipamcmd.AddCommand(IpamAllocateCommand())
+	ipamcmd.AddCommand(IpamReleaseCommand())
+	return ipamcmd
+}
+
+func init() {
+	rootCmd.AddCommand(IpamCommand())
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/jsonpb"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifests hold vms as YAML or JSON, in any of the forms -o yaml and -o
+// json print: a single vm, a list of vms under "vms", or a bare list. YAML
+// files can hold several documents separated by ---.
+
+var manifestExts = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+var yamlSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// readManifests reads the vms in paths, which are files, directories searched
+// for .yaml, .yml and .json files, or - for stdin. A hostname may only be
+// used once.
+func readManifests(paths []string) ([]*pbv2.Virtualmachine, error) {
+	var vms []*pbv2.Virtualmachine
+	seen := map[string]string{}
+	add := func(name string, data []byte) error {
+		found, err := parseManifest(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		for _, vm := range found {
+			if prev, ok := seen[vm.Hostname]; ok {
+				return fmt.Errorf("%s: vm %s is also in %s", name, vm.Hostname, prev)
+			}
+			seen[vm.Hostname] = name
+		}
+		vms = append(vms, found...)
+		return nil
+	}
+
+	for _, path := range paths {
+		if path == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return nil, err
+			}
+			if err := add("stdin", data); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if err := add(f, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return vms, nil
+}
+
+// manifestFiles is path, or the manifests under it when it is a directory
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && manifestExts[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// parseManifest reads the vms in one file
+func parseManifest(data []byte) ([]*pbv2.Virtualmachine, error) {
+	var vms []*pbv2.Virtualmachine
+	for i, doc := range yamlSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		found, err := parseDocument([]byte(doc))
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i+1, err)
+		}
+		vms = append(vms, found...)
+	}
+
+	for _, vm := range vms {
+		switch {
+		case vm.Hostname == "":
+			return nil, fmt.Errorf("vm without a hostname")
+		case vm.Project == "":
+			return nil, fmt.Errorf("vm %s has no project", vm.Hostname)
+		case vm.Role == "":
+			return nil, fmt.Errorf("vm %s has no role", vm.Hostname)
+		}
+	}
+	return vms, nil
+}
+
+func parseDocument(doc []byte) ([]*pbv2.Virtualmachine, error) {
+	js, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	js = bytes.TrimSpace(js)
+
+	var items []json.RawMessage
+	switch {
+	case bytes.HasPrefix(js, []byte("[")):
+		if err := json.Unmarshal(js, &items); err != nil {
+			return nil, err
+		}
+	case bytes.HasPrefix(js, []byte("{")):
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(js, &fields); err != nil {
+			return nil, err
+		}
+		if _, ok := fields["vms"]; !ok {
+			items = []json.RawMessage{js}
+			break
+		}
+		var list pbv2.ListVirtualmachinesResponse
+		if err := jsonpb.Unmarshal(bytes.NewReader(js), &list); err != nil {
+			return nil, err
+		}
+		return list.Vms, nil
+	default:
+		return nil, fmt.Errorf("want a vm, a list of vms or an object with vms")
+	}
+
+	vms := make([]*pbv2.Virtualmachine, len(items))
+	for i, item := range items {
+		vms[i] = &pbv2.Virtualmachine{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(item), vms[i]); err != nil {
+			return nil, err
+		}
+	}
+	return vms, nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// output is the --output format
+var output string
+
+// column of the table and wide output, wide columns only show in wide
+type column struct {
+	header string
+	wide   bool
+	value  func(*pbv2.Virtualmachine) string
+}
+
+var vmColumns = []column{
+	{header: "HOSTNAME", value: func(vm *pbv2.Virtualmachine) string { return vm.Hostname }},
+	{header: "PROJECT", value: func(vm *pbv2.Virtualmachine) string { return vm.Project }},
+	{header: "ROLE", value: func(vm *pbv2.Virtualmachine) string { return vm.Role }},
+	{header: "ADDRESSES", wide: true, value: func(vm *pbv2.Virtualmachine) string { return strings.Join(vm.Addresses, ",") }},
+	{header: "LABELS", wide: true, value: func(vm *pbv2.Virtualmachine) string { return formatLabels(vm.Labels, ",") }},
+	{header: "SIZING", wide: true, value: func(vm *pbv2.Virtualmachine) string { return formatSizing(vm.Sizing) }},
+}
+
+// formatSizing writes the sizes that are set, e.g. 2cpu,4096MB,40GB
+func formatSizing(s *pbv2.Sizing) string {
+	var parts []string
+	if s.GetCpus() > 0 {
+		parts = append(parts, fmt.Sprintf("%dcpu", s.Cpus))
+	}
+	if s.GetMemoryMb() > 0 {
+		parts = append(parts, fmt.Sprintf("%dMB", s.MemoryMb))
+	}
+	if s.GetDiskGb() > 0 {
+		parts = append(parts, fmt.Sprintf("%dGB", s.DiskGb))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseSizing reads sizes as formatSizing writes them, nil when s is empty
+func parseSizing(s string) (*pbv2.Sizing, error) {
+	if s == "" {
+		return nil, nil
+	}
+	sizing := &pbv2.Sizing{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		i := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return nil, fmt.Errorf("sizing %q is not like 2cpu,4096MB,40GB", s)
+		}
+		n, err := strconv.ParseInt(part[:i], 10, 32)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("sizing %q has a bad size %q", s, part)
+		}
+		switch strings.ToLower(part[i:]) {
+		case "cpu":
+			sizing.Cpus = int32(n)
+		case "mb":
+			sizing.MemoryMb = int32(n)
+		case "gb":
+			sizing.DiskGb = int32(n)
+		default:
+			return nil, fmt.Errorf("sizing %q is not like 2cpu,4096MB,40GB", s)
+		}
+	}
+	return sizing, nil
+}
+
+// formatLabels writes labels as name=value pairs sorted by name
+func formatLabels(labels map[string]string, sep string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + "=" + labels[name]
+	}
+	return strings.Join(names, sep)
+}
+
+// parseLabels reads name=value pairs
+func parseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := map[string]string{}
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("label %q is not name=value", p)
+		}
+		labels[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// addOutputFlag adds -o to cmd
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format: table, wide, json, yaml, jsonpath=TEMPLATE or go-template=TEMPLATE")
+}
+
+// printer writes vms in one output format
+type printer struct {
+	format   string
+	jsonpath *jsonpath.JSONPath
+	template *template.Template
+}
+
+// newPrinter checks format and parses its template
+func newPrinter(format string) (*printer, error) {
+	format, tmpl, _ := strings.Cut(format, "=")
+	p := &printer{format: format}
+	switch format {
+	case "", "table", "wide", "json", "yaml":
+		return p, nil
+	case "jsonpath", "go-template":
+		if tmpl == "" {
+			return nil, fmt.Errorf("output %s needs a template, e.g. %s={.hostname}", format, format)
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want table, wide, json, yaml, jsonpath=... or go-template=...", format)
+	}
+
+	if format == "jsonpath" {
+		p.jsonpath = jsonpath.New("output")
+		if err := p.jsonpath.Parse(tmpl); err != nil {
+			return nil, fmt.Errorf("bad jsonpath %q: %v", tmpl, err)
+		}
+		return p, nil
+	}
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("bad go-template %q: %v", tmpl, err)
+	}
+	p.template = t
+	return p, nil
+}
+
+// outputPrinter is the printer for --output, commands get it before making
+// any change so a bad format changes nothing
+func outputPrinter() (*printer, error) {
+	p, err := newPrinter(output)
+	if err != nil {
+		return nil, &usageError{err: err}
+	}
+	return p, nil
+}
+
+// print writes vms to stdout. json, yaml and the templates are given msg,
+// which holds vms, as REST clients would see it.
+func (p *printer) print(msg proto.Message, vms ...*pbv2.Virtualmachine) error {
+	return p.write(os.Stdout, msg, vms)
+}
+
+func (p *printer) write(w io.Writer, msg proto.Message, vms []*pbv2.Virtualmachine) error {
+	switch p.format {
+	case "", "table":
+		return writeTable(w, vms, false)
+	case "wide":
+		return writeTable(w, vms, true)
+	}
+
+	var buf bytes.Buffer
+	m := jsonpb.Marshaler{OrigName: true, EmitDefaults: true, Indent: "  "}
+	if err := m.Marshal(&buf, msg); err != nil {
+		return err
+	}
+
+	switch p.format {
+	case "json":
+		buf.WriteByte('\n')
+		_, err := buf.WriteTo(w)
+		return err
+	case "yaml":
+		out, err := yaml.JSONToYAML(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		return err
+	}
+	if p.jsonpath != nil {
+		return p.jsonpath.Execute(w, data)
+	}
+	return p.template.Execute(w, data)
+}
+
+func writeTable(w io.Writer, vms []*pbv2.Virtualmachine, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	var cols []column
+	for _, c := range vmColumns {
+		if !c.wide || wide {
+			cols = append(cols, c)
+		}
+	}
+
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = c.header
+	}
+	fmt.Fprintln(tw, strings.Join(row, "\t"))
+	for _, vm := range vms {
+		for i, c := range cols {
+			row[i] = c.value(vm)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestNewPrinter(t *testing.T) {
+	for format, valid := range map[string]bool{
+		"":                          true,
+		"table":                     true,
+		"wide":                      true,
+		"json":                      true,
+		"yaml":                      true,
+		"jsonpath={.hostname}":      true,
+		"go-template={{.hostname}}": true,
+		"jsonpath":                  false,
+		"jsonpath={.hostname":       false,
+		"go-template={{.hostname":   false,
+		"csv":                       false,
+		"jsonpath=":                 false,
+	} {
+		if _, err := newPrinter(format); (err == nil) != valid {
+			t.Errorf("newPrinter(%q) error = %v, want valid %v", format, err, valid)
+		}
+	}
+}
+
+func TestPrinterWrite(t *testing.T) {
+	vm := &pbv2.Virtualmachine{
+		Hostname:  "web01",
+		Project:   "billing",
+		Role:      "web",
+		Labels:    map[string]string{"env": "prod", "app": "shop"},
+		Addresses: []string{"10.0.0.5"},
+		Sizing:    &pbv2.Sizing{Cpus: 2, MemoryMb: 4096},
+	}
+	tests := []struct {
+		format string
+		want   []string
+		absent []string
+	}{
+		{"table", []string{"HOSTNAME", "web01", "billing"}, []string{"ADDRESSES", "10.0.0.5"}},
+		{"wide", []string{"ADDRESSES", "10.0.0.5", "app=shop,env=prod", "2cpu,4096MB"}, nil},
+		{"json", []string{`"hostname": "web01"`, `"env": "prod"`, `"memory_mb": 4096`}, nil},
+		{"yaml", []string{"hostname: web01", "- 10.0.0.5"}, nil},
+		{"jsonpath={.project}/{.role}", []string{"billing/web"}, nil},
+		{"go-template={{.hostname}} {{index .labels \"env\"}}", []string{"web01 prod"}, nil},
+	}
+	for _, tt := range tests {
+		p, err := newPrinter(tt.format)
+		if err != nil {
+			t.Fatalf("newPrinter(%q): %v", tt.format, err)
+		}
+		var buf bytes.Buffer
+		if err := p.write(&buf, vm, []*pbv2.Virtualmachine{vm}); err != nil {
+			t.Errorf("%s: write: %v", tt.format, err)
+			continue
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("%s output does not contain %q:\n%s", tt.format, s, buf.String())
+			}
+		}
+		for _, s := range tt.absent {
+			if strings.Contains(buf.String(), s) {
+				t.Errorf("%s output contains %q:\n%s", tt.format, s, buf.String())
+			}
+		}
+	}
+}
+
+func TestFormatSizing(t *testing.T) {
+	tests := []struct {
+		sizing *pbv2.Sizing
+		want   string
+	}{
+		{nil, ""},
+		{&pbv2.Sizing{}, ""},
+		{&pbv2.Sizing{Cpus: 2, MemoryMb: 4096, DiskGb: 40}, "2cpu,4096MB,40GB"},
+		{&pbv2.Sizing{DiskGb: 40}, "40GB"},
+	}
+	for _, tt := range tests {
+		if got := formatSizing(tt.sizing); got != tt.want {
+			t.Errorf("formatSizing(%v) = %q, want %q", tt.sizing, got, tt.want)
+		}
+	}
+}
+
+func TestParseSizing(t *testing.T) {
+	tests := []struct {
+		sizing string
+		want   string
+		err    bool
+	}{
+		{"", "", false},
+		{"2cpu,4096MB,40GB", "2cpu,4096MB,40GB", false},
+		{"40GB", "40GB", false},
+		{" 4096mb , 2CPU ", "2cpu,4096MB", false},
+		{"2", "", true},
+		{"cpu", "", true},
+		{"2cpus", "", true},
+		{"0cpu", "", true},
+		{"-2cpu", "", true},
+		{"9999999999MB", "", true},
+		{"2cpu,", "", true},
+	}
+	for _, tt := range tests {
+		s, err := parseSizing(tt.sizing)
+		if (err != nil) != tt.err || formatSizing(s) != tt.want {
+			t.Errorf("parseSizing(%q) = %q, %v, want %q", tt.sizing, formatSizing(s), err, tt.want)
+		}
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	got, err := parseLabels([]string{"env=prod", " app = shop ", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "app=shop,empty=,env=prod"; formatLabels(got, ",") != want {
+		t.Errorf("parseLabels = %s, want %s", formatLabels(got, ","), want)
+	}
+	for _, bad := range []string{"env", "=prod"} {
+		if _, err := parseLabels([]string{bad}); err == nil {
+			t.Errorf("parseLabels(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var (
+	projectOwnerTeam   string
+	projectContact     string
+	projectCostCenter  string
+	projectDescription string
+)
+
+// projectFlags maps the flags of project create and update to the fields
+// they set
+var projectFlags = []struct {
+	flag, field, usage string
+	value              *string
+}{
+	{"owner-team", "owner_team", "team responsible for the project's vms", &projectOwnerTeam},
+	{"contact", "contact", "where to reach the owners, e.g. an email address", &projectContact},
+	{"cost-center", "cost_center", "cost center the project's vms are charged to", &projectCostCenter},
+	{"description", "description", "what the project is", &projectDescription},
+}
+
+func addProjectFlags(cmd *cobra.Command) {
+	for _, f := range projectFlags {
+		cmd.Flags().StringVar(f.value, f.flag, "", f.usage)
+	}
+}
+
+func flagProject(name string) *pbv2.Project {
+	return &pbv2.Project{
+		Name:        name,
+		OwnerTeam:   projectOwnerTeam,
+		Contact:     projectContact,
+		CostCenter:  projectCostCenter,
+		Description: projectDescription,
+	}
+}
+
+func writeProjects(w io.Writer, projects []*pbv2.Project, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	header := "NAME\tOWNER TEAM\tCONTACT\tCOST CENTER\tVMS"
+	if wide {
+		header += "\tDESCRIPTION"
+	}
+	fmt.Fprintln(tw, header)
+	for _, p := range projects {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d", p.Name, p.OwnerTeam, p.Contact, p.CostCenter, p.VmCount)
+		if wide {
+			fmt.Fprintf(tw, "\t%s", p.Description)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// printProject writes p as a table, or in the --output format
+func printProject(p *printer, project *pbv2.Project) error {
+	if p.tabular() {
+		return writeProjects(os.Stdout, []*pbv2.Project{project}, p.format == "wide")
+	}
+	return p.print(project)
+}
+
+// ProjectCreateCommandFunc r
+func ProjectCreateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	project, err := projects.CreateProject(ctx, &pbv2.CreateProjectRequest{Project: flagProject(args[0])})
+	if err != nil {
+		return callError(err, "could not create project %s", args[0])
+	}
+	return printProject(p, project)
+}
+
+// ProjectListCommandFunc r
+func ProjectListCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := projects.ListProjects(ctx, &pbv2.ListProjectsRequest{})
+	if err != nil {
+		return callError(err, "could not list projects")
+	}
+	if p.tabular() {
+		return writeProjects(os.Stdout, r.Projects, p.format == "wide")
+	}
+	return p.print(r)
+}
+
+// ProjectGetCommandFunc r
+func ProjectGetCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	project, err := projects.GetProject(ctx, &pbv2.GetProjectRequest{Name: args[0]})
+	if err != nil {
+		return callError(err, "could not get project %s", args[0])
+	}
+	if !p.tabular() {
+		return p.print(project)
+	}
+	fmt.Printf("Name:        %s\n", project.Name)
+	fmt.Printf("Owner team:  %s\n", project.OwnerTeam)
+	fmt.Printf("Contact:     %s\n", project.Contact)
+	fmt.Printf("Cost center: %s\n", project.CostCenter)
+	fmt.Printf("Description: %s\n", project.Description)
+	fmt.Printf("VMs:         %d\n", project.VmCount)
+	return nil
+}
+
+// ProjectUpdateCommandFunc r
+func ProjectUpdateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	// Only the fields given as flags are changed
+	mask := &fieldmaskpb.FieldMask{}
+	for _, f := range projectFlags {
+		if cmd.Flags().Changed(f.flag) {
+			mask.Paths = append(mask.Paths, f.field)
+		}
+	}
+	if len(mask.Paths) == 0 {
+		return usageErrorf("nothing to update, give at least one of --owner-team, --contact, --cost-center or --description")
+	}
+	project, err := projects.UpdateProject(ctx, &pbv2.UpdateProjectRequest{
+		Name:       args[0],
+		Project:    flagProject(args[0]),
+		UpdateMask: mask,
+	})
+	if err != nil {
+		return callError(err, "could not update project %s", args[0])
+	}
+	return printProject(p, project)
+}
+
+// ProjectDeleteCommandFunc r
+func ProjectDeleteCommandFunc(cmd *cobra.Command, args []string) error {
+	if _, err := projects.DeleteProject(ctx, &pbv2.DeleteProjectRequest{Name: args[0]}); err != nil {
+		return callError(err, "could not delete project %s", args[0])
+	}
+	fmt.Println("Deleted:", args[0])
+	return nil
+}
+
+// ProjectCreateCommand r
+func ProjectCreateCommand() *cobra.Command {
+	createcmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a project vms can be created in",
+		Long: `Create adds a project. Names are 1 to 63 lower case letters, digits or '-',
+starting and ending with a letter or digit.`,
+		Args: cobra.ExactArgs(1),
+		RunE: ProjectCreateCommandFunc,
+	}
+	addProjectFlags(createcmd)
+	addOutputFlag(createcmd)
+	return createcmd
+}
+
+// ProjectListCommand r
+func ProjectListCommand() *cobra.Command {
+	listcmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the projects and how many vms they have",
+		Args:  cobra.NoArgs,
+		RunE:  ProjectListCommandFunc,
+	}
+	addOutputFlag(listcmd)
+	return listcmd
+}
+
+// ProjectGetCommand r
+func ProjectGetCommand() *cobra.Command {
+	getcmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Show a project",
+		Args:  cobra.ExactArgs(1),
+		RunE:  ProjectGetCommandFunc,
+	}
+	addOutputFlag(getcmd)
+	return getcmd
+}
+
+// ProjectUpdateCommand r
+func ProjectUpdateCommand() *cobra.Command {
+	updatecmd := &cobra.Command{
+		Use:   "update <name> [--owner-team <team>] [--contact <contact>] [--cost-center <cc>] [--description <text>]",
+		Short: "Change a project's details, projects cannot be renamed",
+		Args:  cobra.ExactArgs(1),
+		RunE:  ProjectUpdateCommandFunc,
+	}
+	addProjectFlags(updatecmd)
+	addOutputFlag(updatecmd)
+	return updatecmd
+}
+
+// ProjectDeleteCommand r
+func ProjectDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a project that has no vms and no role allows",
+		Args:  cobra.ExactArgs(1),
+		RunE:  ProjectDeleteCommandFunc,
+	}
+}
+
+// ProjectCommand r
+func ProjectCommand() *cobra.Command {
+	projectcmd := &cobra.Command{
+		Use:   "project",
+		Short: "Manage the projects vms belong to",
+		Long: `Project manages the projects vms belong to. Vms can only be created in
+projects that exist, and projects can only be deleted once they are empty
+and no role lists them in --allowed-project.`,
+	}
+
+	projectcmd.AddCommand(ProjectCreateCommand())
+	projectcmd.AddCommand(ProjectListCommand())
+	projectcmd.AddCommand(ProjectGetCommand())
+	projectcmd.AddCommand(ProjectUpdateCommand())
+	projectcmd.AddCommand(ProjectDeleteCommand())
+	return projectcmd
+}
+
+func init() {
+	rootCmd.AddCommand(ProjectCommand())
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/achanno/sreapi/inventory"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	renderFile   string
+	renderDomain string
+)
+
+// renderBlock writes block as the managed block of renderFile, or prints it
+// with its markers when no file is given
+func renderBlock(block string, perm os.FileMode) error {
+	if renderFile == "" {
+		fmt.Print(inventory.BeginMarker + "\n" + block + inventory.EndMarker + "\n")
+		return nil
+	}
+
+	path, err := homedir.Expand(renderFile)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	old, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	text, err := inventory.ReplaceBlock(string(old), block)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if text == string(old) {
+		fmt.Fprintf(os.Stderr, "%s is up to date\n", path)
+		return nil
+	}
+	err = writeFileAtomic(path, perm, func(w io.Writer) error {
+		_, err := io.WriteString(w, text)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "updated %s\n", path)
+	return nil
+}
+
+// RenderHostsCommandFunc r
+func RenderHostsCommandFunc(cmd *cobra.Command, args []string) error {
+	vms, err := listVMs(project, role)
+	if err != nil {
+		return err
+	}
+	return renderBlock(inventory.Hosts(vms, renderDomain), 0o644)
+}
+
+// RenderSSHConfigCommandFunc r
+func RenderSSHConfigCommandFunc(cmd *cobra.Command, args []string) error {
+	var cfg inventory.SSHConfig
+	if err := viper.UnmarshalKey("ssh", &cfg); err != nil {
+		return usageErrorf("ssh settings in %s: %v", viper.ConfigFileUsed(), err)
+	}
+	vms, err := listVMs(project, role)
+	if err != nil {
+		return err
+	}
+	return renderBlock(inventory.SSH(vms, renderDomain, cfg), 0o600)
+}
+
+// addRenderFlags adds the flags every render command takes
+func addRenderFlags(cmd *cobra.Command, file string) {
+	cmd.Flags().StringVar(&project, "project", "", "only vms in this project")
+	cmd.Flags().StringVar(&role, "role", "", "only vms with this role")
+	cmd.Flags().StringVar(&renderDomain, "domain", "", "domain to qualify the hostnames with")
+	cmd.Flags().StringVarP(&renderFile, "file", "f", "", "file to update the managed block of, e.g. "+file+", stdout when not set")
+}
+
+// RenderHostsCommand r
+func RenderHostsCommand() *cobra.Command {
+	hostscmd := &cobra.Command{
+		Use:   "hosts [--file /etc/hosts]",
+		Short: "Render /etc/hosts lines for the vms' addresses",
+		Long: `Hosts renders a line per vm address, mapping it to the vm's name in --domain
+and its hostname.
+
+With --file only the managed block of the file is replaced, it is added to
+the end of the file the first time.`,
+		Args: cobra.NoArgs,
+		RunE: RenderHostsCommandFunc,
+	}
+	addRenderFlags(hostscmd, "/etc/hosts")
+	return hostscmd
+}
+
+// RenderSSHConfigCommand r
+func RenderSSHConfigCommand() *cobra.Command {
+	sshcmd := &cobra.Command{
+		Use:   "ssh-config [--file ~/.ssh/config]",
+		Short: "Render ssh Host entries for the vms",
+		Long: `Ssh-config renders a Host entry per vm, connecting to its first address or
+its name in --domain.
+
+User, port, identity file and bastion come from the ssh section of the
+config file, for every vm, per project or per role. Role settings win over
+project settings, which win over the defaults:
+
+  ssh:
+    user: admin
+    identity_file: ~/.ssh/id_ed25519
+    projects:
+      billing:
+        bastion: jump.billing.example.com
+    roles:
+      db:
+        user: dba
+
+With --file only the managed block of the file is replaced, it is added to
+the end of the file the first time. ssh uses the first value it finds, so
+move the block above any 'Host *' section.`,
+		Args: cobra.NoArgs,
+		RunE: RenderSSHConfigCommandFunc,
+	}
+	addRenderFlags(sshcmd, "~/.ssh/config")
+	return sshcmd
+}
+
+// RenderCommand r
+func RenderCommand() *cobra.Command {
+	rendercmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render config files from the vms",
+	}
+
+	rendercmd.AddCommand(RenderHostsCommand())
+	rendercmd.AddCommand(RenderSSHConfigCommand())
+	return rendercmd
+}
+
+func init() {
+	rootCmd.AddCommand(RenderCommand())
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var (
+	roleDescription     string
+	roleLabels          []string
+	roleAllowedProjects []string
+	roleClasses         []string
+)
+
+// roleFlags maps the flags of role update to the fields they set
+var roleFlags = []struct{ flag, field string }{
+	{"description", "description"},
+	{"cpus", "default_sizing"},
+	{"memory-mb", "default_sizing"},
+	{"disk-gb", "default_sizing"},
+	{"label", "default_labels"},
+	{"allowed-project", "allowed_projects"},
+	{"class", "classes"},
+}
+
+func addRoleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&roleDescription, "description", "", "what vms with the role do")
+	addSizingFlags(cmd, "new vms with the role")
+	cmd.Flags().StringSliceVarP(&roleLabels, "label", "l", nil, "label new vms with the role get as name=value, can be repeated")
+	cmd.Flags().StringSliceVar(&roleAllowedProjects, "allowed-project", nil, "project vms with the role may be in, any project when not set, can be repeated")
+	cmd.Flags().StringSliceVar(&roleClasses, "class", nil, "config management class of vms with the role, can be repeated")
+}
+
+func flagRole(cmd *cobra.Command, name string) (*pbv2.Role, error) {
+	l, err := parseLabels(roleLabels)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.Role{
+		Name:            name,
+		Description:     roleDescription,
+		DefaultSizing:   flagSizing(cmd),
+		DefaultLabels:   l,
+		AllowedProjects: roleAllowedProjects,
+		Classes:         roleClasses,
+	}, nil
+}
+
+func writeRoles(w io.Writer, roles []*pbv2.Role, wide bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	header := "NAME\tVMS\tDESCRIPTION"
+	if wide {
+		header += "\tSIZING\tLABELS\tPROJECTS\tCLASSES"
+	}
+	fmt.Fprintln(tw, header)
+	for _, r := range roles {
+		fmt.Fprintf(tw, "%s\t%d\t%s", r.Name, r.VmCount, r.Description)
+		if wide {
+			fmt.Fprintf(tw, "\t%s\t%s\t%s\t%s", formatSizing(r.DefaultSizing), formatLabels(r.DefaultLabels, ","),
+				strings.Join(r.AllowedProjects, ","), strings.Join(r.Classes, ","))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// printRole writes r as a table, or in the --output format
+func printRole(p *printer, r *pbv2.Role) error {
+	if p.tabular() {
+		return writeRoles(os.Stdout, []*pbv2.Role{r}, p.format == "wide")
+	}
+	return p.print(r)
+}
+
+// RoleCreateCommandFunc r
+func RoleCreateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := flagRole(cmd, args[0])
+	if err != nil {
+		return &usageError{err: err}
+	}
+	r, err = roles.CreateRole(ctx, &pbv2.CreateRoleRequest{Role: r})
+	if err != nil {
+		return callError(err, "could not create role %s", args[0])
+	}
+	return printRole(p, r)
+}
+
+// RoleListCommandFunc r
+func RoleListCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := roles.ListRoles(ctx, &pbv2.ListRolesRequest{})
+	if err != nil {
+		return callError(err, "could not list roles")
+	}
+	if p.tabular() {
+		return writeRoles(os.Stdout, r.Roles, p.format == "wide")
+	}
+	return p.print(r)
+}
+
+// RoleDescribeCommandFunc r
+func RoleDescribeCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := roles.GetRole(ctx, &pbv2.GetRoleRequest{Name: args[0]})
+	if err != nil {
+		return callError(err, "could not get role %s", args[0])
+	}
+	if !p.tabular() {
+		return p.print(r)
+	}
+	projects := strings.Join(r.AllowedProjects, ", ")
+	if projects == "" {
+		projects = "any"
+	}
+	fmt.Printf("Name:             %s\n", r.Name)
+	fmt.Printf("Description:      %s\n", r.Description)
+	fmt.Printf("Default sizing:   %s\n", formatSizing(r.DefaultSizing))
+	fmt.Printf("Default labels:   %s\n", formatLabels(r.DefaultLabels, ", "))
+	fmt.Printf("Allowed projects: %s\n", projects)
+	fmt.Printf("Classes:          %s\n", strings.Join(r.Classes, ", "))
+	fmt.Printf("VMs:              %d\n", r.VmCount)
+	return nil
+}
+
+// RoleUpdateCommandFunc r
+func RoleUpdateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	r, err := flagRole(cmd, args[0])
+	if err != nil {
+		return &usageError{err: err}
+	}
+	// Only the fields given as flags are changed
+	mask := &fieldmaskpb.FieldMask{}
+	for _, f := range roleFlags {
+		if cmd.Flags().Changed(f.flag) && !slices.Contains(mask.Paths, f.field) {
+			mask.Paths = append(mask.Paths, f.field)
+		}
+	}
+	if len(mask.Paths) == 0 {
+		return usageErrorf("nothing to update, give at least one of --description, --cpus, --memory-mb, --disk-gb, --label, --allowed-project or --class")
+	}
+	// The sizes not given are kept
+	if r.DefaultSizing != nil {
+		old, err := roles.GetRole(ctx, &pbv2.GetRoleRequest{Name: args[0]})
+		if err != nil {
+			return callError(err, "could not get role %s", args[0])
+		}
+		if s := old.DefaultSizing; s != nil {
+			if !cmd.Flags().Changed("cpus") {
+				r.DefaultSizing.Cpus = s.Cpus
+			}
+			if !cmd.Flags().Changed("memory-mb") {
+				r.DefaultSizing.MemoryMb = s.MemoryMb
+			}
+			if !cmd.Flags().Changed("disk-gb") {
+				r.DefaultSizing.DiskGb = s.DiskGb
+			}
+		}
+	}
+	r, err = roles.UpdateRole(ctx, &pbv2.UpdateRoleRequest{Name: args[0], Role: r, UpdateMask: mask})
+	if err != nil {
+		return callError(err, "could not update role %s", args[0])
+	}
+	return printRole(p, r)
+}
+
+// RoleDeleteCommandFunc r
+func RoleDeleteCommandFunc(cmd *cobra.Command, args []string) error {
+	if _, err := roles.DeleteRole(ctx, &pbv2.DeleteRoleRequest{Name: args[0]}); err != nil {
+		return callError(err, "could not delete role %s", args[0])
+	}
+	fmt.Println("Deleted:", args[0])
+	return nil
+}
+
+// RoleCreateCommand r
+func RoleCreateCommand() *cobra.Command {
+	createcmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a role vms can have",
+		Long: `Create adds a role. Names are 1 to 63 lower case letters, digits or '-',
+starting and ending with a letter or digit.
+
+New vms with the role get its default labels they do not set themselves, and
+its default sizing when they have none. With --allowed-project vms with the
+role can only be in those projects. The classes are added to the vm's by
+'sreapi enc'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: RoleCreateCommandFunc,
+	}
+	addRoleFlags(createcmd)
+	addOutputFlag(createcmd)
+	return createcmd
+}
+
+// RoleListCommand r
+func RoleListCommand() *cobra.Command {
+	listcmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the roles and how many vms have them",
+		Args:  cobra.NoArgs,
+		RunE:  RoleListCommandFunc,
+	}
+	addOutputFlag(listcmd)
+	return listcmd
+}
+
+// RoleDescribeCommand r
+func RoleDescribeCommand() *cobra.Command {
+	describecmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Show a role and how many vms have it",
+		Args:  cobra.ExactArgs(1),
+		RunE:  RoleDescribeCommandFunc,
+	}
+	addOutputFlag(describecmd)
+	return describecmd
+}
+
+// RoleUpdateCommand r
+func RoleUpdateCommand() *cobra.Command {
+	updatecmd := &cobra.Command{
+		Use:   "update <name> [flags]",
+		Short: "Change a role's details, roles cannot be renamed",
+		Long: `Update changes the fields given as flags. --label, --allowed-project and
+--class replace the whole list. Vms that already have the role keep what they
+got from it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: RoleUpdateCommandFunc,
+	}
+	addRoleFlags(updatecmd)
+	addOutputFlag(updatecmd)
+	return updatecmd
+}
+
+// RoleDeleteCommand r
+func RoleDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a role no vm has",
+		Args:  cobra.ExactArgs(1),
+		RunE:  RoleDeleteCommandFunc,
+	}
+}
+
+// RoleCommand r
+func RoleCommand() *cobra.Command {
+	rolecmd := &cobra.Command{
+		Use:   "role",
+		Short: "Manage the roles vms have",
+		Long: `Role manages the catalog of roles vms have. Vms can only be created with
+roles that exist, and roles can only be deleted once no vm has them.`,
+	}
+
+	rolecmd.AddCommand(RoleCreateCommand())
+	rolecmd.AddCommand(RoleListCommand())
+	rolecmd.AddCommand(RoleDescribeCommand())
+	rolecmd.AddCommand(RoleUpdateCommand())
+	rolecmd.AddCommand(RoleDeleteCommand())
+	return rolecmd
+}
+
+func init() {
+	rootCmd.AddCommand(RoleCommand())
+}
@@ -22,10 +22,11 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/achanno/sreapi/certs"
-	pb "github.com/achanno/sreapi/protobuf"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -35,44 +36,79 @@ import (
 const (
 	port = ":5555"
 	host = "localhost"
-	apiv = "1"
 )
 
 var (
 	cfgFile      string
+	serverAddr   string
+	timeout      time.Duration
 	demoKeyPair  *tls.Certificate
 	demoCertPool *x509.CertPool
 	conn         *grpc.ClientConn
-	c            pb.VirtualmachinesClient
+	c            pbv2.VirtualmachinesClient
+	ipam         pbv2.IpamClient
+	projects     pbv2.ProjectsClient
+	roles        pbv2.RolesClient
 	ctx          context.Context
 	cancel       context.CancelFunc
 )
 
+// started is set once the command line has been parsed and checked, errors
+// before that are usage errors
+var started bool
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "sreapi",
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	//	Run: func(cmd *cobra.Command, args []string) { },
+	Short: "Manage the vm inventory",
+	Long: `sreapi serves the vm inventory and manages it from the command line.
+
+` + exitCodesHelp,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// cobra only checks these after this hook, they are usage errors too
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return err
+		}
+		started = true
+		// --timeout bounds each call, see callTimeout
+		ctx, cancel = context.WithCancel(context.Background())
+		if needsServer(cmd) {
+			if err := dial(host + port); err != nil {
+				return err
+			}
+		}
+		return startTrace(cmd, args)
+	},
+	// Execute reports errors itself, on stderr with an exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	endTrace()
+	cancel()
+	if conn != nil {
+		conn.Close()
+	}
+	if err == nil {
+		return
 	}
 
-	defer conn.Close()
-	defer cancel()
+	if !started {
+		err = &usageError{err: err}
+	}
+	reportError(os.Stderr, err)
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	os.Exit(code)
 }
 
 func init() {
@@ -82,6 +118,9 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.sreapi.yaml)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 10*time.Second, "how long each call to the server may take")
+	rootCmd.PersistentFlags().StringVar(&traceExporter, "trace-exporter", "", "export traces to otlp, stdout or file, off when empty")
+	rootCmd.PersistentFlags().StringVar(&traceFile, "trace-file", "sreapi-traces.json", "file the file trace exporter writes to")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -100,18 +139,46 @@ func init() {
 		log.Fatalf("Bad cert")
 	}
 
-	// Initialize grpc connection
-	creds := credentials.NewClientTLSFromCert(demoCertPool, host+port)
-	conntmp, err := grpc.Dial(host+port, grpc.WithTransportCredentials(creds))
-	if err != nil {
-		log.Fatalf("Error connecting to grpc: %v", err)
+	// The context is set up once the command line is parsed
+	ctx, cancel = context.Background(), func() {}
+}
+
+// needsServer reports whether cmd calls the server, the server itself and
+// help do not
+func needsServer(cmd *cobra.Command) bool {
+	if _, ok := cmd.Annotations["server"]; ok {
+		return false
 	}
-	conn = conntmp
+	for p := cmd; p != nil; p = p.Parent() {
+		if p.Name() == "help" || p.Name() == "completion" {
+			return false
+		}
+	}
+	return true
+}
 
-	// Initialize context
-	c = pb.NewVirtualmachinesClient(conn)
-	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+// dial sets up the connection to the server at addr and the clients using it
+func dial(addr string) error {
+	creds := credentials.NewClientTLSFromCert(demoCertPool, addr)
+	cc, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds), grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(callTimeout))
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %v", addr, err)
+	}
+	conn, serverAddr = cc, addr
+	c = pbv2.NewVirtualmachinesClient(conn)
+	ipam = pbv2.NewIpamClient(conn)
+	projects = pbv2.NewProjectsClient(conn)
+	roles = pbv2.NewRolesClient(conn)
+	return nil
+}
 
+// callTimeout gives each call its own --timeout, so commands that page
+// through or batch many vms are not cut short
+func callTimeout(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return invoker(ctx, method, req, reply, cc, opts...)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -136,6 +203,7 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		// stderr, so it does not end up in piped output
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestCallTimeout(t *testing.T) {
+	defer func(d time.Duration) { timeout = d }(timeout)
+	timeout = time.Minute
+
+	var deadlines []time.Time
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		d, ok := ctx.Deadline()
+		if !ok {
+			t.Fatalf("%s has no deadline", method)
+		}
+		deadlines = append(deadlines, d)
+		return nil
+	}
+	for _, method := range []string{"/sreapi.v2.Virtualmachines/List", "/sreapi.v2.Virtualmachines/List"} {
+		if err := callTimeout(context.Background(), method, nil, nil, nil, invoker); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(deadlines) != 2 || !deadlines[1].After(deadlines[0]) {
+		t.Errorf("deadlines = %v, want one per call", deadlines)
+	}
+	if left := time.Until(deadlines[1]); left < 59*time.Second {
+		t.Errorf("call had %v left, want about --timeout", left)
+	}
+}
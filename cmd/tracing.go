@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/achanno/sreapi/tracing"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	traceExporter   string
+	traceFile       string
+	span            trace.Span
+	shutdownTracing func(context.Context) error
+)
+
+// startTrace starts a span covering the command, its rpcs are traced as
+// children. TRACEPARENT in the environment makes the command part of the
+// caller's trace.
+func startTrace(cmd *cobra.Command, args []string) error {
+	if _, ok := cmd.Annotations["server"]; ok {
+		// The server sets up its own tracing
+		return nil
+	}
+
+	var err error
+	shutdownTracing, err = tracing.Setup(ctx, "sreapi-cli", traceExporter, traceFile)
+	if err != nil {
+		return usageErrorf("could not set up tracing: %v", err)
+	}
+
+	if tp := os.Getenv("TRACEPARENT"); tp != "" {
+		carrier := propagation.MapCarrier{"traceparent": tp, "tracestate": os.Getenv("TRACESTATE")}
+		ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+	}
+	ctx, span = otel.Tracer("github.com/achanno/sreapi/cmd").Start(ctx, cmd.CommandPath())
+	return nil
+}
+
+// endTrace ends the command span and flushes it
+func endTrace() {
+	if span != nil {
+		span.End()
+	}
+	if shutdownTracing != nil {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Printf("Could not flush traces: %v", err)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Import and export formats
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+)
+
+var (
+	transferFormat string
+	importFile     string
+	upsert         bool
+	pageSize       int32
+	batchSize      int
+)
+
+// csvField maps a csv column to a vm field, optional columns may be left out
+// of imports
+type csvField struct {
+	name     string
+	optional bool
+	get      func(*pbv2.Virtualmachine) string
+	set      func(*pbv2.Virtualmachine, string) error
+}
+
+var csvFields = []csvField{
+	{name: "hostname",
+		get: func(vm *pbv2.Virtualmachine) string { return vm.Hostname },
+		set: func(vm *pbv2.Virtualmachine, v string) error { vm.Hostname = v; return nil }},
+	{name: "project",
+		get: func(vm *pbv2.Virtualmachine) string { return vm.Project },
+		set: func(vm *pbv2.Virtualmachine, v string) error { vm.Project = v; return nil }},
+	{name: "role",
+		get: func(vm *pbv2.Virtualmachine) string { return vm.Role },
+		set: func(vm *pbv2.Virtualmachine, v string) error { vm.Role = v; return nil }},
+	// labels are name=value pairs separated by ;
+	{name: "labels", optional: true,
+		get: func(vm *pbv2.Virtualmachine) string { return formatLabels(vm.Labels, ";") },
+		set: func(vm *pbv2.Virtualmachine, v string) error {
+			if v == "" {
+				return nil
+			}
+			l, err := parseLabels(strings.Split(v, ";"))
+			vm.Labels = l
+			return err
+		}},
+	// addresses are separated by ;
+	{name: "addresses", optional: true,
+		get: func(vm *pbv2.Virtualmachine) string { return strings.Join(vm.Addresses, ";") },
+		set: func(vm *pbv2.Virtualmachine, v string) error {
+			if v != "" {
+				vm.Addresses = strings.Split(v, ";")
+			}
+			return nil
+		}},
+	// sizing is written as by formatSizing, e.g. 2cpu,4096MB,40GB
+	{name: "sizing", optional: true,
+		get: func(vm *pbv2.Virtualmachine) string { return formatSizing(vm.Sizing) },
+		set: func(vm *pbv2.Virtualmachine, v string) (err error) {
+			vm.Sizing, err = parseSizing(v)
+			return err
+		}},
+}
+
+// eachVMPage calls f with every page of vms matching req
+func eachVMPage(req *pbv2.ListVirtualmachinesRequest, f func([]*pbv2.Virtualmachine) error) error {
+	for {
+		r, err := c.List(ctx, req)
+		if err != nil {
+			return callError(err, "could not list vms")
+		}
+		if err := f(r.Vms); err != nil {
+			return err
+		}
+		if r.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = r.NextPageToken
+	}
+}
+
+// transferFormatFor is --format, or the format name's extension implies
+func transferFormatFor(name string) (string, error) {
+	format := transferFormat
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".csv":
+			format = formatCSV
+		case ".jsonl", ".ndjson":
+			format = formatJSONL
+		default:
+			return "", usageErrorf("cannot tell the format of %q, set --format csv or jsonl", name)
+		}
+	}
+	if format != formatCSV && format != formatJSONL {
+		return "", usageErrorf("unknown format %q, want csv or jsonl", format)
+	}
+	return format, nil
+}
+
+// VMExportCommandFunc r
+func VMExportCommandFunc(cmd *cobra.Command, args []string) error {
+	if transferFormat != formatCSV && transferFormat != formatJSONL {
+		return usageErrorf("unknown format %q, want csv or jsonl", transferFormat)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	cw := csv.NewWriter(w)
+	if transferFormat == formatCSV {
+		header := make([]string, len(csvFields))
+		for i, f := range csvFields {
+			header[i] = f.name
+		}
+		cw.Write(header)
+	}
+
+	m := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	req := &pbv2.ListVirtualmachinesRequest{Project: project, Role: role, PageSize: pageSize}
+	return eachVMPage(req, func(vms []*pbv2.Virtualmachine) error {
+		for _, vm := range vms {
+			if transferFormat == formatJSONL {
+				if err := m.Marshal(w, vm); err != nil {
+					return err
+				}
+				w.WriteByte('\n')
+				continue
+			}
+			row := make([]string, len(csvFields))
+			for i, f := range csvFields {
+				row[i] = f.get(vm)
+			}
+			cw.Write(row)
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+// importRow is a vm and the line it was read from
+type importRow struct {
+	line int
+	vm   *pbv2.Virtualmachine
+}
+
+// lineError is a problem with one line of the import
+type lineError struct {
+	line int
+	msg  string
+}
+
+func (e lineError) String() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// readCSV reads vms from csv with a header naming the columns, columns it
+// does not know are ignored
+func readCSV(r io.Reader) ([]importRow, []lineError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading csv header: %v", err)
+	}
+
+	cols := make([]*csvField, len(header))
+	found := map[string]bool{}
+	for i, name := range header {
+		for j := range csvFields {
+			if strings.EqualFold(strings.TrimSpace(name), csvFields[j].name) {
+				cols[i] = &csvFields[j]
+				found[csvFields[j].name] = true
+			}
+		}
+		if cols[i] == nil {
+			fmt.Fprintf(os.Stderr, "Ignoring unknown column %q\n", name)
+		}
+	}
+	for _, f := range csvFields {
+		if !found[f.name] && !f.optional {
+			return nil, nil, fmt.Errorf("csv header has no %s column", f.name)
+		}
+	}
+
+	var rows []importRow
+	var lerrs []lineError
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return rows, lerrs, nil
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			lerrs = append(lerrs, lineError{perr.Line, perr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		if len(rec) != len(header) {
+			lerrs = append(lerrs, lineError{line, fmt.Sprintf("has %d fields, the header has %d", len(rec), len(header))})
+			continue
+		}
+		vm := &pbv2.Virtualmachine{}
+		var ferr error
+		for i, v := range rec {
+			if cols[i] != nil && ferr == nil {
+				ferr = cols[i].set(vm, strings.TrimSpace(v))
+			}
+		}
+		if ferr != nil {
+			lerrs = append(lerrs, lineError{line, ferr.Error()})
+			continue
+		}
+		rows = append(rows, importRow{line, vm})
+	}
+}
+
+// readJSONL reads a vm from each line, blank lines are skipped
+func readJSONL(r io.Reader) ([]importRow, []lineError, error) {
+	var rows []importRow
+	var lerrs []lineError
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		vm := &pbv2.Virtualmachine{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(b), vm); err != nil {
+			lerrs = append(lerrs, lineError{line, err.Error()})
+			continue
+		}
+		rows = append(rows, importRow{line, vm})
+	}
+	return rows, lerrs, sc.Err()
+}
+
+// checkRows drops rows that are missing fields or repeat a hostname
+func checkRows(rows []importRow) ([]importRow, []lineError) {
+	var ok []importRow
+	var lerrs []lineError
+	seen := map[string]int{}
+	for _, r := range rows {
+		switch {
+		case r.vm.Hostname == "":
+			lerrs = append(lerrs, lineError{r.line, "hostname is required"})
+		case r.vm.Project == "":
+			lerrs = append(lerrs, lineError{r.line, "project is required"})
+		case r.vm.Role == "":
+			lerrs = append(lerrs, lineError{r.line, "role is required"})
+		case seen[r.vm.Hostname] != 0:
+			lerrs = append(lerrs, lineError{r.line, fmt.Sprintf("vm %s is already on line %d", r.vm.Hostname, seen[r.vm.Hostname])})
+		default:
+			seen[r.vm.Hostname] = r.line
+			ok = append(ok, r)
+		}
+	}
+	return ok, lerrs
+}
+
+// failedRow finds the row the server blamed for rejecting a batch, from the
+// vm or address named in a ResourceInfo or the index in a BadRequest field,
+// or -1
+func failedRow(st *status.Status, batch []importRow) int {
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ResourceInfo:
+			for i, r := range batch {
+				if d.ResourceType == "address" {
+					// The row taking an address another vm already has
+					if r.vm.Hostname != d.Owner && hasAddress(r.vm, d.ResourceName) {
+						return i
+					}
+				} else if r.vm.Hostname == d.ResourceName {
+					return i
+				}
+			}
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				var i int
+				if _, err := fmt.Sscanf(v.Field, "vms[%d]", &i); err == nil && i >= 0 && i < len(batch) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// hasAddress reports whether vm has addr, which the server writes in its
+// canonical form
+func hasAddress(vm *pbv2.Virtualmachine, addr string) bool {
+	for _, a := range vm.Addresses {
+		if ip, err := netip.ParseAddr(a); a == addr || err == nil && ip.Unmap().String() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// importBatch creates batch in one call. A row the server rejects is
+// reported and the rest are sent again, anything else fails the import.
+func importBatch(batch []importRow) (int, []lineError, error) {
+	var lerrs []lineError
+	for len(batch) > 0 {
+		vms := make([]*pbv2.Virtualmachine, len(batch))
+		for i, r := range batch {
+			vms[i] = r.vm
+		}
+		_, err := c.BatchCreate(ctx, &pbv2.BatchCreateVirtualmachinesRequest{Vms: vms, Upsert: upsert})
+		if err == nil {
+			return len(batch), lerrs, nil
+		}
+
+		st := status.Convert(err)
+		i := failedRow(st, batch)
+		if i < 0 {
+			return 0, lerrs, callError(err, "could not import lines %d to %d", batch[0].line, batch[len(batch)-1].line)
+		}
+		lerrs = append(lerrs, lineError{batch[i].line, st.Message()})
+		batch = append(batch[:i:i], batch[i+1:]...)
+	}
+	return 0, lerrs, nil
+}
+
+// VMImportCommandFunc r
+func VMImportCommandFunc(cmd *cobra.Command, args []string) error {
+	if batchSize < 1 || batchSize > 1000 {
+		return usageErrorf("--batch-size must be between 1 and 1000")
+	}
+	format, err := transferFormatFor(importFile)
+	if err != nil {
+		return err
+	}
+
+	in := os.Stdin
+	if importFile != "-" {
+		f, err := os.Open(importFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var rows []importRow
+	var lerrs []lineError
+	if format == formatCSV {
+		rows, lerrs, err = readCSV(in)
+	} else {
+		rows, lerrs, err = readJSONL(in)
+	}
+	if err != nil {
+		return err
+	}
+	rows, bad := checkRows(rows)
+	lerrs = append(lerrs, bad...)
+
+	imported := 0
+	for start := 0; start < len(rows); start += batchSize {
+		end := min(start+batchSize, len(rows))
+		n, bad, err := importBatch(rows[start:end])
+		imported += n
+		lerrs = append(lerrs, bad...)
+		if err != nil {
+			for _, le := range lerrs {
+				fmt.Fprintln(os.Stderr, le)
+			}
+			fmt.Printf("Imported %d vms before failing.\n", imported)
+			return err
+		}
+	}
+
+	for _, le := range lerrs {
+		fmt.Fprintln(os.Stderr, le)
+	}
+	fmt.Printf("Imported %d vms, %d lines failed.\n", imported, len(lerrs))
+	if len(lerrs) > 0 {
+		return fmt.Errorf("%d lines were not imported", len(lerrs))
+	}
+	return nil
+}
+
+// VMExportCommand r
+func VMExportCommand() *cobra.Command {
+	vmcommand := &cobra.Command{
+		Use:   "export --format csv|jsonl",
+		Short: "Write every vm to stdout as csv or json lines",
+		Args:  cobra.NoArgs,
+		RunE:  VMExportCommandFunc,
+	}
+
+	vmcommand.Flags().StringVar(&transferFormat, "format", formatCSV, "csv or jsonl")
+	vmcommand.Flags().StringVar(&project, "project", "", "only export vms in project")
+	vmcommand.Flags().StringVar(&role, "role", "", "only export vms with role")
+	vmcommand.Flags().Int32Var(&pageSize, "page-size", 500, "vms fetched per call")
+	return vmcommand
+}
+
+// VMImportCommand r
+func VMImportCommand() *cobra.Command {
+	vmcommand := &cobra.Command{
+		Use:   "import -f <file|->",
+		Short: "Create vms from csv or json lines",
+		Long: `Import creates the vms in a csv file, with a header naming the hostname,
+project, role and optionally labels, addresses and sizing columns, or a json
+lines file with a vm per line. Labels in csv are name=value pairs and
+addresses are separated by ;, sizing is written like 2cpu,4096MB,40GB. Vms
+without a sizing get their role's.
+
+Lines that are invalid or rejected by the server are reported on stderr and
+the rest are imported. Vms are sent in batches, each created in one
+transaction. Existing vms fail their line unless --upsert replaces them.`,
+		Args: cobra.NoArgs,
+		RunE: VMImportCommandFunc,
+	}
+
+	vmcommand.Flags().StringVarP(&importFile, "filename", "f", "", "file to import, - for stdin")
+	vmcommand.Flags().StringVar(&transferFormat, "format", "", "csv or jsonl, by default from the file extension")
+	vmcommand.Flags().BoolVar(&upsert, "upsert", false, "replace vms that already exist instead of failing")
+	vmcommand.Flags().IntVar(&batchSize, "batch-size", 500, "vms created per call, at most 1000")
+	vmcommand.MarkFlagRequired("filename")
+	return vmcommand
+}
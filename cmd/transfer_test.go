@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rowString is the line and fields of an imported row
+func rowString(r importRow) string {
+	vm := r.vm
+	return fmt.Sprintf("%d %s/%s/%s [%s] [%s] [%s]", r.line, vm.Hostname, vm.Project, vm.Role,
+		formatLabels(vm.Labels, ","), strings.Join(vm.Addresses, ","), formatSizing(vm.Sizing))
+}
+
+func joinRows(rows []importRow) string {
+	s := make([]string, len(rows))
+	for i, r := range rows {
+		s[i] = rowString(r)
+	}
+	return strings.Join(s, "; ")
+}
+
+func joinLineErrors(lerrs []lineError) string {
+	s := make([]string, len(lerrs))
+	for i, e := range lerrs {
+		s[i] = e.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		csv   string
+		rows  string
+		lerrs string
+		err   string
+	}{
+		{
+			name: "all columns",
+			csv:  "hostname,project,role,labels,addresses\nweb01,billing,web,env=prod;team=pay,10.0.0.5;2001:db8::5\n",
+			rows: "2 web01/billing/web [env=prod,team=pay] [10.0.0.5,2001:db8::5] []",
+		},
+		{
+			name: "optional columns left out",
+			csv:  "hostname,project,role\nweb01,billing,web\n",
+			rows: "2 web01/billing/web [] [] []",
+		},
+		{
+			name: "empty labels and addresses",
+			csv:  "hostname,project,role,labels,addresses\nweb01,billing,web,,\n",
+			rows: "2 web01/billing/web [] [] []",
+		},
+		{
+			name: "columns in any order and case, spaces trimmed",
+			csv:  "Role, Hostname ,PROJECT,owner\nweb , web01,billing,alice\n",
+			rows: "2 web01/billing/web [] [] []",
+		},
+		{
+			name: "sizing",
+			csv:  "hostname,project,role,sizing\nweb01,billing,web,\"2cpu,4096MB,40GB\"\nweb02,billing,web,\n",
+			rows: "2 web01/billing/web [] [] [2cpu,4096MB,40GB]; 3 web02/billing/web [] [] []",
+		},
+		{
+			name:  "bad sizing",
+			csv:   "hostname,project,role,sizing\nweb01,billing,web,2 cpus\n",
+			lerrs: `line 2: sizing "2 cpus" is not like 2cpu,4096MB,40GB`,
+		},
+		{
+			name:  "bad label",
+			csv:   "hostname,project,role,labels\nweb01,billing,web,env\nweb02,billing,web,env=prod\n",
+			rows:  "3 web02/billing/web [env=prod] [] []",
+			lerrs: `line 2: label "env" is not name=value`,
+		},
+		{
+			name:  "wrong number of fields",
+			csv:   "hostname,project,role\nweb01,billing\nweb02,billing,web\n",
+			rows:  "3 web02/billing/web [] [] []",
+			lerrs: "line 2: has 2 fields, the header has 3",
+		},
+		{
+			name:  "quoting",
+			csv:   "hostname,project,role\n\"web01\",billing,\"we\"b\"\nweb02,billing,web\n",
+			rows:  "3 web02/billing/web [] [] []",
+			lerrs: `line 2: extraneous or missing " in quoted-field`,
+		},
+		{
+			name: "no role column",
+			csv:  "hostname,project\nweb01,billing\n",
+			err:  "csv header has no role column",
+		},
+		{
+			name: "empty",
+			csv:  "",
+			err:  "reading csv header",
+		},
+	}
+	for _, tt := range tests {
+		rows, lerrs, err := readCSV(strings.NewReader(tt.csv))
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: readCSV error = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: readCSV error = %v", tt.name, err)
+			continue
+		}
+		if got := joinRows(rows); got != tt.rows {
+			t.Errorf("%s: readCSV rows = %q, want %q", tt.name, got, tt.rows)
+		}
+		if got := joinLineErrors(lerrs); got != tt.lerrs {
+			t.Errorf("%s: readCSV line errors = %q, want %q", tt.name, got, tt.lerrs)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	vms := []*pbv2.Virtualmachine{
+		{
+			Hostname:  "web01",
+			Project:   "billing",
+			Role:      "web",
+			Labels:    map[string]string{"env": "prod", "team": "pay"},
+			Addresses: []string{"10.0.0.5", "2001:db8::5"},
+			Sizing:    &pbv2.Sizing{Cpus: 2, MemoryMb: 4096, DiskGb: 40},
+		},
+		{Hostname: "web02", Project: "billing", Role: "web", Sizing: &pbv2.Sizing{DiskGb: 20}},
+		{Hostname: "web03", Project: "billing", Role: "web"},
+	}
+
+	// Written as export writes them
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	header := make([]string, len(csvFields))
+	for i, f := range csvFields {
+		header[i] = f.name
+	}
+	cw.Write(header)
+	for _, vm := range vms {
+		row := make([]string, len(csvFields))
+		for i, f := range csvFields {
+			row[i] = f.get(vm)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+
+	rows, lerrs, err := readCSV(&buf)
+	if err != nil || len(lerrs) > 0 {
+		t.Fatalf("readCSV: %v %s", err, joinLineErrors(lerrs))
+	}
+	want := make([]importRow, len(vms))
+	for i, vm := range vms {
+		want[i] = importRow{i + 2, vm}
+	}
+	if got := joinRows(rows); got != joinRows(want) {
+		t.Errorf("csv round trip = %s, want %s", got, joinRows(want))
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	jsonl := `{"hostname": "web01", "project": "billing", "role": "web", "labels": {"env": "prod"}, "addresses": ["10.0.0.5"], "sizing": {"cpus": 2, "memory_mb": 4096, "disk_gb": 40}}
+
+{"hostname": "web02", "project": "billing", "role": "web", "sizing": {"diskGb": 20}}
+{"hostname": "web03", "colour": "blue"}
+not json
+{"hostname": "web04", "project": "billing", "role": "web", "sizing": {"cpus": "two"}}
+`
+	rows, lerrs, err := readJSONL(strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatalf("readJSONL: %v", err)
+	}
+	want := "1 web01/billing/web [env=prod] [10.0.0.5] [2cpu,4096MB,40GB]; 3 web02/billing/web [] [] [20GB]"
+	if got := joinRows(rows); got != want {
+		t.Errorf("readJSONL rows = %q, want %q", got, want)
+	}
+	var lines []int
+	for _, e := range lerrs {
+		lines = append(lines, e.line)
+	}
+	if fmt.Sprint(lines) != "[4 5 6]" {
+		t.Errorf("readJSONL line errors on lines %v, want [4 5 6]: %s", lines, joinLineErrors(lerrs))
+	}
+}
+
+func TestCheckRows(t *testing.T) {
+	row := func(line int, hostname, project, role string) importRow {
+		return importRow{line, &pbv2.Virtualmachine{Hostname: hostname, Project: project, Role: role}}
+	}
+	rows, lerrs := checkRows([]importRow{
+		row(2, "web01", "billing", "web"),
+		row(3, "", "billing", "web"),
+		row(4, "web02", "", "web"),
+		row(5, "web03", "billing", ""),
+		row(6, "web01", "billing", "web"),
+		row(7, "web04", "billing", "web"),
+	})
+	if got, want := joinRows(rows), "2 web01/billing/web [] [] []; 7 web04/billing/web [] [] []"; got != want {
+		t.Errorf("checkRows rows = %q, want %q", got, want)
+	}
+	want := "line 3: hostname is required; line 4: project is required; line 5: role is required; line 6: vm web01 is already on line 2"
+	if got := joinLineErrors(lerrs); got != want {
+		t.Errorf("checkRows line errors = %q, want %q", got, want)
+	}
+}
+
+func TestFailedRow(t *testing.T) {
+	batch := []importRow{
+		{2, &pbv2.Virtualmachine{Hostname: "web01", Addresses: []string{"10.0.0.5"}}},
+		{3, &pbv2.Virtualmachine{Hostname: "web02", Addresses: []string{"::ffff:10.0.0.6", "2001:0db8::6"}}},
+	}
+	resource := func(typ, name, owner string) *status.Status {
+		st, err := status.New(codes.FailedPrecondition, "rejected").WithDetails(
+			&errdetails.ResourceInfo{ResourceType: typ, ResourceName: name, Owner: owner})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st
+	}
+	field := func(f string) *status.Status {
+		st, err := status.New(codes.InvalidArgument, "rejected").WithDetails(
+			&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: f}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return st
+	}
+	tests := []struct {
+		name string
+		st   *status.Status
+		want int
+	}{
+		{"no details", status.New(codes.Internal, "rejected"), -1},
+		{"vm", resource("vm", "web02", ""), 1},
+		{"unknown vm", resource("vm", "db01", ""), -1},
+		{"address", resource("address", "10.0.0.5", "db01"), 0},
+		{"mapped address", resource("address", "10.0.0.6", "db01"), 1},
+		{"non canonical address", resource("address", "2001:db8::6", "db01"), 1},
+		{"address owned by the row", resource("address", "10.0.0.5", "web01"), -1},
+		{"field", field("vms[1].hostname"), 1},
+		{"field out of range", field("vms[2].hostname"), -1},
+		{"other field", field("upsert"), -1},
+	}
+	for _, tt := range tests {
+		if got := failedRow(tt.st, batch); got != tt.want {
+			t.Errorf("%s: failedRow = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTransferFormatFor(t *testing.T) {
+	tests := []struct {
+		format, name string
+		want         string
+		err          bool
+	}{
+		{"", "vms.csv", formatCSV, false},
+		{"", "VMS.CSV", formatCSV, false},
+		{"", "vms.jsonl", formatJSONL, false},
+		{"", "vms.ndjson", formatJSONL, false},
+		{"", "vms.txt", "", true},
+		{"", "-", "", true},
+		{formatCSV, "-", formatCSV, false},
+		{formatJSONL, "vms.csv", formatJSONL, false},
+		{"xml", "vms.csv", "", true},
+	}
+	defer func(f string) { transferFormat = f }(transferFormat)
+	for _, tt := range tests {
+		transferFormat = tt.format
+		got, err := transferFormatFor(tt.name)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("transferFormatFor(%q) with --format %q = %q, %v, want %q", tt.name, tt.format, got, err, tt.want)
+		}
+	}
+}
@@ -2,118 +2,309 @@ package cmd
 
 import (
 	"errors"
-	pb "github.com/achanno/sreapi/protobuf"
+	"fmt"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
 	vmserver "github.com/achanno/sreapi/virtualmachineserver"
 	"github.com/spf13/cobra"
-	"log"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
-	project string
-	role    string
+	project         string
+	role            string
+	httpPort        string
+	shutdownTimeout time.Duration
+	logLevel        string
+	rateLimits      []string
+	rateLimit       string
+	labels          []string
+	addresses       []string
+	dnsAddr         string
+	dnsZone         string
+	serverENC       string
+	allocateFrom    string
+	autoHostname    bool
+	reserveToken    string
+	cpus            int32
+	memoryMb        int32
+	diskGb          int32
 )
 
+// sizingFlags are the flags flagSizing reads
+var sizingFlags = []string{"cpus", "memory-mb", "disk-gb"}
+
+func addSizingFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().Int32Var(&cpus, "cpus", 0, "cpus of "+what)
+	cmd.Flags().Int32Var(&memoryMb, "memory-mb", 0, "memory of "+what+" in MB")
+	cmd.Flags().Int32Var(&diskGb, "disk-gb", 0, "disk of "+what+" in GB")
+}
+
+// flagSizing is the sizing given with --cpus, --memory-mb and --disk-gb, nil
+// when none of them is set
+func flagSizing(cmd *cobra.Command) *pbv2.Sizing {
+	for _, f := range sizingFlags {
+		if cmd.Flags().Changed(f) {
+			return &pbv2.Sizing{Cpus: cpus, MemoryMb: memoryMb, DiskGb: diskGb}
+		}
+	}
+	return nil
+}
+
+// parseRateLimit parses RATE:BURST, e.g. 5:10 for 5 calls a second in bursts of 10
+func parseRateLimit(s string) (vmserver.RateLimit, error) {
+	r, b, ok := strings.Cut(s, ":")
+	if !ok {
+		return vmserver.RateLimit{}, fmt.Errorf("rate limit %q is not RATE:BURST", s)
+	}
+	rate, err := strconv.ParseFloat(r, 64)
+	if err != nil {
+		return vmserver.RateLimit{}, fmt.Errorf("rate limit %q: %v", s, err)
+	}
+	burst, err := strconv.Atoi(b)
+	if err != nil {
+		return vmserver.RateLimit{}, fmt.Errorf("rate limit %q: %v", s, err)
+	}
+	l := vmserver.RateLimit{Rate: rate, Burst: burst}
+	if err := l.Validate(); err != nil {
+		return vmserver.RateLimit{}, fmt.Errorf("rate limit %q: %v", s, err)
+	}
+	return l, nil
+}
+
 // VMDeleteCommandFunc r
-func VMDeleteCommandFunc(cmd *cobra.Command, args []string) {
-	r, err := c.Delete(ctx, &pb.DeleteRequest{XApi: apiv, Hostname: args[0]})
+func VMDeleteCommandFunc(cmd *cobra.Command, args []string) error {
+	_, err := c.Delete(ctx, &pbv2.DeleteVirtualmachineRequest{Hostname: args[0]})
 	if err != nil {
-		log.Fatalf("Could not delete vm: %v", err)
+		return callError(err, "could not delete vm %s", args[0])
 	}
-	log.Print("Deleted: ", args[0], " Sucess: ", r.Success)
+	fmt.Println("Deleted:", args[0])
+	return nil
 }
 
 // VMUpdateCommandFunc r
-func VMUpdateCommandFunc(cmd *cobra.Command, args []string) {
-	r, err := c.Update(ctx, &pb.UpdateRequest{XApi: apiv, Hostname: args[1], Project: args[2], Role: args[3], Oldhostname: args[0]})
+func VMUpdateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	l, err := parseLabels(labels)
 	if err != nil {
-		log.Fatalf("Could not list vms: %v", err)
+		return &usageError{err: err}
 	}
-	log.Print("Updated: ", args[0], r.Success)
+	// Labels, addresses and sizing are kept unless their flags replace them
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"hostname", "project", "role"}}
+	if cmd.Flags().Changed("label") {
+		mask.Paths = append(mask.Paths, "labels")
+	}
+	if cmd.Flags().Changed("address") {
+		mask.Paths = append(mask.Paths, "addresses")
+	}
+	sizing := flagSizing(cmd)
+	if sizing != nil {
+		mask.Paths = append(mask.Paths, "sizing")
+	}
+	vm, err := c.Update(ctx, &pbv2.UpdateVirtualmachineRequest{
+		Hostname:   args[0],
+		Vm:         &pbv2.Virtualmachine{Hostname: args[1], Project: args[2], Role: args[3], Labels: l, Addresses: addresses, Sizing: sizing},
+		UpdateMask: mask,
+	})
+	if err != nil {
+		return callError(err, "could not update vm %s", args[0])
+	}
+	return p.print(vm, vm)
 }
 
 // VMCreateCommandFunc r
-func VMCreateCommandFunc(cmd *cobra.Command, args []string) {
-	r, err := c.Create(ctx, &pb.CreateRequest{XApi: apiv, Hostname: args[0], Project: args[1], Role: args[2]})
+func VMCreateCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
+	}
+	l, err := parseLabels(labels)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	vm := &pbv2.Virtualmachine{Project: project, Role: role, Labels: l, Addresses: addresses, Sizing: flagSizing(cmd)}
+	if !autoHostname {
+		vm.Hostname, vm.Project, vm.Role = args[0], args[1], args[2]
+	}
+	vm, err = c.Create(ctx, &pbv2.CreateVirtualmachineRequest{
+		Vm:               vm,
+		AllocateFrom:     allocateFrom,
+		AutoHostname:     autoHostname,
+		ReservationToken: reserveToken,
+	})
 	if err != nil {
-		log.Fatalf("Could not create vm: %v", err)
+		if autoHostname {
+			return callError(err, "could not create a vm in project %s", project)
+		}
+		return callError(err, "could not create vm %s", args[0])
 	}
-	log.Print("Created VM: ", r.Success)
+	// Scripts creating vms with --auto want the name they got
+	if autoHostname && !cmd.Flags().Changed("output") {
+		fmt.Println(vm.Hostname)
+		return nil
+	}
+	return p.print(vm, vm)
 }
 
-// VMGetCommandFunc r
-func VMGetCommandFunc(cmd *cobra.Command, args []string) {
-	r, err := c.Get(ctx, &pb.GetRequest{XApi: apiv, Hostname: args[0]})
+// VMAllocateHostnameCommandFunc r
+func VMAllocateHostnameCommandFunc(cmd *cobra.Command, args []string) error {
+	r, err := c.AllocateHostname(ctx, &pbv2.AllocateHostnameRequest{Project: project, Role: role})
 	if err != nil {
-		log.Fatalf("Could not list vms: %v", err)
+		return callError(err, "could not allocate a hostname in project %s", project)
 	}
-	log.Print("Found stuff: ", r.Vm.Hostname, r.Vm.Project, r.Vm.Role)
+	fmt.Println(r.Hostname, r.ReservationToken)
+	return nil
 }
 
-// VMListCommandFunc r
-func VMListCommandFunc(cmd *cobra.Command, args []string) {
-	if project != "" {
-		log.Print("Found project: ", project)
+// VMNamingTemplateCommandFunc r
+func VMNamingTemplateCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		t, err := c.GetNamingTemplate(ctx, &pbv2.GetNamingTemplateRequest{Project: args[0]})
+		if err != nil {
+			return callError(err, "could not get the naming template of project %s", args[0])
+		}
+		fmt.Println(t.Template)
+		return nil
+	}
+	t, err := c.SetNamingTemplate(ctx, &pbv2.SetNamingTemplateRequest{
+		NamingTemplate: &pbv2.NamingTemplate{Project: args[0], Template: args[1]},
+	})
+	if err != nil {
+		return callError(err, "could not set the naming template of project %s", args[0])
 	}
+	fmt.Println(t.Template)
+	return nil
+}
 
-	if role != "" {
-		log.Print("Found role: ", role)
+// VMGetCommandFunc r
+func VMGetCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
 	}
-	var r *pb.ListResponse
-	var errmsg error
-	if project != "" && role != "" {
-		r, errmsg = c.List(ctx, &pb.ListRequest{XApi: apiv, Role: role, Project: project})
-	} else if project != "" && role == "" {
-		r, errmsg = c.List(ctx, &pb.ListRequest{XApi: apiv, Project: project})
-	} else if project == "" && role != "" {
-		r, errmsg = c.List(ctx, &pb.ListRequest{XApi: apiv, Role: role})
-	} else {
-		log.Fatalf("Requires --project or --role")
+	vm, err := c.Get(ctx, &pbv2.GetVirtualmachineRequest{Hostname: args[0]})
+	if err != nil {
+		return callError(err, "could not get vm %s", args[0])
 	}
+	return p.print(vm, vm)
+}
 
-	if errmsg != nil {
-		log.Fatalf("Could not list vms: %v", errmsg)
+// VMListCommandFunc r
+func VMListCommandFunc(cmd *cobra.Command, args []string) error {
+	p, err := outputPrinter()
+	if err != nil {
+		return err
 	}
-
-	for x := range r.Vms {
-		log.Printf("Hostname: %s Project: %s Role: %s", r.Vms[x].Hostname, r.Vms[x].Project, r.Vms[x].Role)
+	r, err := c.List(ctx, &pbv2.ListVirtualmachinesRequest{Project: project, Role: role})
+	if err != nil {
+		return callError(err, "could not list vms")
 	}
+	return p.print(r, r.Vms...)
 }
 
 // VMServerCommandFunc r
-func VMServerCommandFunc(cmd *cobra.Command, args []string) {
+func VMServerCommandFunc(cmd *cobra.Command, args []string) error {
+	opts := vmserver.Options{
+		Addr:            ":5555",
+		ShutdownTimeout: shutdownTimeout,
+		LogLevel:        logLevel,
+		TraceExporter:   traceExporter,
+		TraceFile:       traceFile,
+		DNSAddr:         dnsAddr,
+		DNSZone:         dnsZone,
+		ENCMapping:      serverENC,
+	}
 	if len(args) > 0 {
-		vmserver.Serve(":" + args[0])
-	} else {
-		vmserver.Serve(":5555")
+		opts.Addr = ":" + args[0]
+	}
+	if httpPort != "" {
+		opts.HTTPAddr = ":" + httpPort
+	}
+	if rateLimit != "" {
+		l, err := parseRateLimit(rateLimit)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		opts.DefaultRateLimit = l
+	}
+	if len(rateLimits) > 0 {
+		opts.RateLimits = map[string]vmserver.RateLimit{}
 	}
+	for _, rl := range rateLimits {
+		method, limit, ok := strings.Cut(rl, "=")
+		if !ok {
+			return usageErrorf("rate limit %q is not METHOD=RATE:BURST", rl)
+		}
+		l, err := parseRateLimit(limit)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		opts.RateLimits[method] = l
+	}
+
+	if err := vmserver.Serve(opts); err != nil {
+		return fmt.Errorf("server stopped: %v", err)
+	}
+	return nil
 }
 
 // VMCreateCommand r
 func VMCreateCommand() *cobra.Command {
 	vmcommand := &cobra.Command{
-		Use:   "create <hostname> <project> <role>",
+		Use:   "create <hostname> <project> <role> | --auto --project <project> --role <role>",
 		Short: "Creates new vm",
+		Long: `Create adds a vm. With --auto the server names it from the project's naming
+template, see 'sreapi vm naming-template', and the name is printed.
+
+The vm gets the default labels of its role that it does not set itself, and
+the role's default sizing when --cpus, --memory-mb and --disk-gb are not
+given, see 'sreapi role describe'.
+
+A hostname reserved with 'sreapi vm allocate-hostname' needs the token it
+printed, given with --reservation-token.`,
 		Args: func(cmd *cobra.Command, args []string) error {
+			if autoHostname {
+				if len(args) != 0 || project == "" || role == "" {
+					return errors.New("create --auto requires --project and --role and no arguments")
+				}
+				return nil
+			}
 			if len(args) != 3 {
 				return errors.New("create requires <hostname> <project> <role>")
 			}
 			return nil
 		},
-		Run: VMCreateCommandFunc,
+		RunE: VMCreateCommandFunc,
 	}
+	vmcommand.Flags().BoolVar(&autoHostname, "auto", false, "name the vm from its project's naming template")
+	vmcommand.Flags().StringVar(&reserveToken, "reservation-token", "", "token of the hostname's reservation, from allocate-hostname")
+	vmcommand.MarkFlagsMutuallyExclusive("auto", "reservation-token")
+	vmcommand.Flags().StringVar(&project, "project", "", "project of the vm, with --auto")
+	vmcommand.Flags().StringVar(&role, "role", "", "role of the vm, with --auto")
+	vmcommand.Flags().StringSliceVarP(&labels, "label", "l", nil, "label as name=value, can be repeated")
+	vmcommand.Flags().StringSliceVarP(&addresses, "address", "a", nil, "IPv4 or IPv6 address, can be repeated")
+	vmcommand.Flags().StringVar(&allocateFrom, "allocate-from", "", "also give the vm the next free address in this subnet, e.g. 10.0.0.0/24")
+	addSizingFlags(vmcommand, "the vm, the role's default when not set")
+	addOutputFlag(vmcommand)
 	return vmcommand
 }
 
 // VMListCommand r
 func VMListCommand() *cobra.Command {
 	vmcommand := &cobra.Command{
-		Use:   "list <project/role>",
-		Short: "Lists vms related to projects roles",
-		Run:   VMListCommandFunc,
+		Use:   "list [--project <project>] [--role <role>]",
+		Short: "Lists vms, optionally for a project and role",
+		RunE:  VMListCommandFunc,
 	}
 
 	vmcommand.Flags().StringVar(&project, "project", "", "project name")
 	vmcommand.Flags().StringVar(&role, "role", "", "role name")
+	addOutputFlag(vmcommand)
 	return vmcommand
 }
 
@@ -122,7 +313,7 @@ func VMGetCommand() *cobra.Command {
 	vmcommand := &cobra.Command{
 		Use:   "get <hostname>",
 		Short: "Get vm for hostname",
-		Run:   VMGetCommandFunc,
+		RunE:  VMGetCommandFunc,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return errors.New("get requires <hostname>")
@@ -130,6 +321,7 @@ func VMGetCommand() *cobra.Command {
 			return nil
 		},
 	}
+	addOutputFlag(vmcommand)
 	return vmcommand
 }
 
@@ -144,18 +336,62 @@ func VMUpdateCommand() *cobra.Command {
 			}
 			return nil
 		},
-		Run: VMUpdateCommandFunc,
+		RunE: VMUpdateCommandFunc,
+	}
+	vmcommand.Flags().StringSliceVarP(&labels, "label", "l", nil, "label as name=value, can be repeated")
+	vmcommand.Flags().StringSliceVarP(&addresses, "address", "a", nil, "IPv4 or IPv6 address, can be repeated")
+	addSizingFlags(vmcommand, "the vm, any of them replaces the whole sizing")
+	addOutputFlag(vmcommand)
+	return vmcommand
+}
+
+// VMAllocateHostnameCommand r
+func VMAllocateHostnameCommand() *cobra.Command {
+	vmcommand := &cobra.Command{
+		Use:   "allocate-hostname --project <project> --role <role>",
+		Short: "Reserve the next free hostname for a vm",
+		Long: `Allocate-hostname reserves the next free hostname from the project's naming
+template and prints it with the token that holds it:
+
+  read hostname token < <(sreapi vm allocate-hostname --project billing --role web)
+  sreapi vm create "$hostname" billing web --reservation-token "$token"
+
+The name is held for an hour, or until a vm is created with it. Meanwhile
+only a vm in the same project with the same role and the token can be created
+with it, and no vm can be renamed to it.`,
+		Args: cobra.NoArgs,
+		RunE: VMAllocateHostnameCommandFunc,
 	}
+	vmcommand.Flags().StringVar(&project, "project", "", "project name")
+	vmcommand.Flags().StringVar(&role, "role", "", "role name")
+	vmcommand.MarkFlagRequired("project")
+	vmcommand.MarkFlagRequired("role")
 	return vmcommand
 }
 
+// VMNamingTemplateCommand r
+func VMNamingTemplateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "naming-template <project> [<template>]",
+		Short: "Show or set how a project's vms are named",
+		Long: `Naming-template prints the project's naming template, or sets it when a
+template is given.
+
+{project} and {role} are replaced by the vm's project and role, {nn} by the
+lowest free number padded to as many digits as there are n's. Projects
+without a template use {project}-{role}-{nn}.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: VMNamingTemplateCommandFunc,
+	}
+}
+
 // VMDeleteCommand r
 func VMDeleteCommand() *cobra.Command {
 	vmcommand := &cobra.Command{
 		Use:   "delete <hostname>",
 		Short: "Deletes a vm",
 		Args:  cobra.ExactArgs(1),
-		Run:   VMDeleteCommandFunc,
+		RunE:  VMDeleteCommandFunc,
 	}
 	return vmcommand
 }
@@ -163,11 +399,22 @@ func VMDeleteCommand() *cobra.Command {
 // VMServerCommand r
 func VMServerCommand() *cobra.Command {
 	vmcommand := &cobra.Command{
-		Use:   "server <port>",
-		Short: "Start server on <port>",
-		Args:  cobra.MaximumNArgs(1),
-		Run:   VMServerCommandFunc,
+		Use:         "server <port>",
+		Short:       "Start server on <port>",
+		Args:        cobra.MaximumNArgs(1),
+		RunE:        VMServerCommandFunc,
+		Annotations: map[string]string{"server": ""},
 	}
+
+	vmcommand.Flags().StringVar(&httpPort, "http-port", "", "serve the REST gateway on a separate port")
+	vmcommand.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "how long to drain in-flight requests on SIGINT/SIGTERM")
+	vmcommand.Flags().StringVar(&logLevel, "log-level", "info", "log level: debug, info, warn or error")
+	vmcommand.Flags().StringVar(&rateLimit, "rate-limit", "", "per client limit for every method as RATE:BURST, unlimited when empty")
+	vmcommand.Flags().StringSliceVar(&rateLimits, "method-rate-limit", nil, "per client limit for one method as METHOD=RATE:BURST, e.g. List=5:10")
+	vmcommand.Flags().StringVar(&dnsAddr, "dns-addr", "", "answer dns queries for the vms on this address, e.g. :53")
+	vmcommand.Flags().StringVar(&dnsZone, "dns-zone", "", "zone the dns responder serves the vms' hostnames in")
+	vmcommand.MarkFlagsRequiredTogether("dns-addr", "dns-zone")
+	vmcommand.Flags().StringVar(&serverENC, "enc-mapping", "", "serve puppet and salt classifications from this mapping file at /enc/<hostname>")
 	return vmcommand
 }
 
@@ -183,7 +430,11 @@ func VMCommand() *cobra.Command {
 	vmcmd.AddCommand(VMGetCommand())
 	vmcmd.AddCommand(VMUpdateCommand())
 	vmcmd.AddCommand(VMDeleteCommand())
+	vmcmd.AddCommand(VMAllocateHostnameCommand())
+	vmcmd.AddCommand(VMNamingTemplateCommand())
 	vmcmd.AddCommand(VMServerCommand())
+	vmcmd.AddCommand(VMExportCommand())
+	vmcmd.AddCommand(VMImportCommand())
 	return vmcmd
 }
 
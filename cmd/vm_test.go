@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	vmserver "github.com/achanno/sreapi/virtualmachineserver"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  vmserver.RateLimit
+		valid bool
+	}{
+		{"5:10", vmserver.RateLimit{Rate: 5, Burst: 10}, true},
+		{"0.5:1", vmserver.RateLimit{Rate: 0.5, Burst: 1}, true},
+		{"0:0", vmserver.RateLimit{}, true},
+		{"5", vmserver.RateLimit{}, false},
+		{"x:10", vmserver.RateLimit{}, false},
+		{"5:x", vmserver.RateLimit{}, false},
+		{"5:0", vmserver.RateLimit{}, false},
+		{"-1:10", vmserver.RateLimit{}, false},
+		{"0:10", vmserver.RateLimit{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseRateLimit(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("parseRateLimit(%q) error = %v, want valid %v", tt.in, err, tt.valid)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRateLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,2 +1,24 @@
-protoc --grpc-gateway_out=logtostderr=true:. protobuf/vm.proto -I"${GOPATH}/src/github.com/grpc-ecosystem/grpc-gateway/third_party/googleapis"  -I .
-protoc --go_out=plugins=grpc:. protobuf/vm.proto -I"${GOPATH}/src/github.com/grpc-ecosystem/grpc-gateway/third_party/googleapis"  -I .
+#!/bin/sh
+# Regenerates the grpc, gateway and swagger code of the protos with the
+# generators the checked in code was made with:
+#
+#   github.com/golang/protobuf/protoc-gen-go v1.5.4, the generator that still
+#     has plugins=grpc. It is built on google.golang.org/protobuf v1.33.0,
+#     which is the version the headers of the .pb.go files name.
+#   github.com/grpc-ecosystem/grpc-gateway v1.16.0 protoc-gen-grpc-gateway
+#     and protoc-gen-swagger, whose googleapis protos are used too.
+set -e
+
+bin=$(mktemp -d)
+trap 'rm -rf "$bin"' EXIT
+GOBIN=$bin go install github.com/golang/protobuf/protoc-gen-go@v1.5.4
+GOBIN=$bin go install github.com/grpc-ecosystem/grpc-gateway/protoc-gen-grpc-gateway@v1.16.0
+GOBIN=$bin go install github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger@v1.16.0
+PATH=$bin:$PATH
+googleapis=$(go env GOMODCACHE)/github.com/grpc-ecosystem/grpc-gateway@v1.16.0/third_party/googleapis
+
+for proto in protobuf/vm.proto protobuf/v2/vm.proto protobuf/v2/ipam.proto protobuf/v2/project.proto protobuf/v2/role.proto; do
+  protoc --grpc-gateway_out=logtostderr=true,paths=source_relative:. $proto -I"$googleapis" -I .
+  protoc --go_out=plugins=grpc,paths=source_relative:. $proto -I"$googleapis" -I .
+  protoc --swagger_out=logtostderr=true:openapi $proto -I"$googleapis" -I .
+done
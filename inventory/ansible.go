@@ -0,0 +1,86 @@
+// Package inventory renders the vm inventory in the formats other tools read
+package inventory
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+var groupInvalid = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// GroupName makes an ansible group name from parts, replacing the characters
+// ansible does not allow in group names with _
+func GroupName(parts ...string) string {
+	return groupInvalid.ReplaceAllString(strings.Join(parts, "_"), "_")
+}
+
+// AnsibleGroup is a group in an ansible dynamic inventory
+type AnsibleGroup struct {
+	Hosts    []string `json:"hosts,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// AnsibleMeta holds the hostvars of every host, so ansible does not have to
+// ask for each host with --host
+type AnsibleMeta struct {
+	Hostvars map[string]map[string]interface{} `json:"hostvars"`
+}
+
+// HostVars are the variables describing vm, ansible gets them as hostvars and
+// external node classifiers as parameters
+func HostVars(vm *pbv2.Virtualmachine) map[string]interface{} {
+	labels := vm.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	addrs := vm.Addresses
+	if addrs == nil {
+		addrs = []string{}
+	}
+	return map[string]interface{}{
+		"sreapi_addresses": addrs,
+		"sreapi_hostname":  vm.Hostname,
+		"sreapi_project":   vm.Project,
+		"sreapi_role":      vm.Role,
+		"sreapi_labels":    labels,
+	}
+}
+
+// Ansible builds the document ansible expects from an inventory script run
+// with --list. Hosts are put in project_<project>, role_<role> and
+// label_<name>_<value> groups, which are the children of all.
+func Ansible(vms []*pbv2.Virtualmachine) map[string]interface{} {
+	groups := map[string]*AnsibleGroup{}
+	add := func(group, hostname string) {
+		g, ok := groups[group]
+		if !ok {
+			g = &AnsibleGroup{}
+			groups[group] = g
+		}
+		g.Hosts = append(g.Hosts, hostname)
+	}
+
+	meta := &AnsibleMeta{Hostvars: map[string]map[string]interface{}{}}
+	for _, vm := range vms {
+		meta.Hostvars[vm.Hostname] = HostVars(vm)
+		add(GroupName("project", vm.Project), vm.Hostname)
+		add(GroupName("role", vm.Role), vm.Hostname)
+		for name, value := range vm.Labels {
+			add(GroupName("label", name, value), vm.Hostname)
+		}
+	}
+
+	all := &AnsibleGroup{Children: make([]string, 0, len(groups))}
+	doc := map[string]interface{}{"_meta": meta}
+	for name, g := range groups {
+		sort.Strings(g.Hosts)
+		all.Children = append(all.Children, name)
+		doc[name] = g
+	}
+	sort.Strings(all.Children)
+	doc["all"] = all
+	return doc
+}
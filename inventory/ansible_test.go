@@ -0,0 +1,93 @@
+package inventory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestGroupName(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"project", "billing"}, "project_billing"},
+		{[]string{"role", "web-frontend"}, "role_web_frontend"},
+		{[]string{"label", "env", "prod"}, "label_env_prod"},
+		{[]string{"label", "k8s.io/zone", "eu-west.1"}, "label_k8s_io_zone_eu_west_1"},
+		{[]string{"label", "owner", ""}, "label_owner_"},
+	}
+	for _, tt := range tests {
+		if got := GroupName(tt.parts...); got != tt.want {
+			t.Errorf("GroupName(%q) = %q, want %q", tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestHostVars(t *testing.T) {
+	// a vm without labels or addresses still gets an empty map and list, so
+	// templates can range over them
+	data, err := json.Marshal(HostVars(&pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"sreapi_addresses":[],"sreapi_hostname":"web01","sreapi_labels":{},"sreapi_project":"billing","sreapi_role":"web"}`
+	if string(data) != want {
+		t.Errorf("HostVars = %s, want %s", data, want)
+	}
+}
+
+func TestAnsible(t *testing.T) {
+	doc := Ansible([]*pbv2.Virtualmachine{
+		{Hostname: "web02", Project: "billing", Role: "web", Labels: map[string]string{"env": "prod"}},
+		{Hostname: "db01", Project: "billing", Role: "db", Addresses: []string{"10.0.0.5"}},
+		{Hostname: "web01", Project: "shop-eu", Role: "web", Labels: map[string]string{"env": "prod"}},
+	})
+
+	groups := map[string][]string{
+		"project_billing": {"db01", "web02"},
+		"project_shop_eu": {"web01"},
+		"role_web":        {"web01", "web02"},
+		"role_db":         {"db01"},
+		"label_env_prod":  {"web01", "web02"},
+	}
+	for name, hosts := range groups {
+		g, ok := doc[name].(*AnsibleGroup)
+		if !ok {
+			t.Errorf("no group %s", name)
+			continue
+		}
+		if !reflect.DeepEqual(g.Hosts, hosts) {
+			t.Errorf("group %s hosts = %q, want %q", name, g.Hosts, hosts)
+		}
+	}
+	if len(doc) != len(groups)+2 {
+		t.Errorf("got %d groups, want %d and all and _meta", len(doc), len(groups))
+	}
+
+	all := doc["all"].(*AnsibleGroup)
+	want := []string{"label_env_prod", "project_billing", "project_shop_eu", "role_db", "role_web"}
+	if !reflect.DeepEqual(all.Children, want) || len(all.Hosts) != 0 {
+		t.Errorf("all = %+v, want children %q", all, want)
+	}
+
+	meta := doc["_meta"].(*AnsibleMeta)
+	if len(meta.Hostvars) != 3 {
+		t.Errorf("got hostvars for %d hosts, want 3", len(meta.Hostvars))
+	}
+	if got := meta.Hostvars["db01"]["sreapi_addresses"]; !reflect.DeepEqual(got, []string{"10.0.0.5"}) {
+		t.Errorf("db01 sreapi_addresses = %v", got)
+	}
+}
+
+func TestAnsibleEmpty(t *testing.T) {
+	data, err := json.Marshal(Ansible(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"_meta":{"hostvars":{}},"all":{}}`; string(data) != want {
+		t.Errorf("Ansible(nil) = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,183 @@
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/miekg/dns"
+)
+
+// DefaultTTL is the ttl of generated records when none is given
+const DefaultTTL = 300
+
+// FQDN is the fully qualified name of hostname in zone. Hostnames already in
+// zone are kept as they are.
+func FQDN(hostname, zone string) string {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	zone = strings.Trim(strings.ToLower(zone), ".")
+	if zone == "" || hostname == zone || strings.HasSuffix(hostname, "."+zone) {
+		return dns.Fqdn(hostname)
+	}
+	return dns.Fqdn(hostname + "." + zone)
+}
+
+// IsReverseZone reports whether zone is under in-addr.arpa or ip6.arpa
+func IsReverseZone(zone string) bool {
+	zone = dns.Fqdn(strings.ToLower(zone))
+	return dns.IsSubDomain("in-addr.arpa.", zone) || dns.IsSubDomain("ip6.arpa.", zone)
+}
+
+// ReverseZone is the in-addr.arpa or ip6.arpa zone holding the reverse names
+// of the addresses in p, widened to the octet or nibble boundary reverse
+// names are split on. The zone of 10.0.0.0/12 is 10.in-addr.arpa, which
+// holds all of 10.0.0.0/8, so whoever serves it should only answer for the
+// addresses in p.
+func ReverseZone(p netip.Prefix) string {
+	addr := p.Masked().Addr().Unmap()
+	var labels []string
+	suffix := "in-addr.arpa."
+	if addr.Is4() {
+		a := addr.As4()
+		for i := 0; i < p.Bits()/8 && i < len(a); i++ {
+			labels = append(labels, fmt.Sprint(a[i]))
+		}
+	} else {
+		suffix = "ip6.arpa."
+		a := addr.As16()
+		for i := 0; i < p.Bits()/4; i++ {
+			nibble := a[i/2] >> 4
+			if i%2 == 1 {
+				nibble = a[i/2] & 0xf
+			}
+			labels = append(labels, fmt.Sprintf("%x", nibble))
+		}
+	}
+	var b strings.Builder
+	for i := len(labels) - 1; i >= 0; i-- {
+		b.WriteString(labels[i] + ".")
+	}
+	return b.String() + suffix
+}
+
+// AddressRecord is the A or AAAA record of addr for name
+func AddressRecord(name string, addr netip.Addr, ttl uint32) dns.RR {
+	if addr.Is4() {
+		return &dns.A{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl}, A: addr.AsSlice()}
+	}
+	return &dns.AAAA{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: ttl}, AAAA: addr.AsSlice()}
+}
+
+// PTRRecord points the reverse name of addr at target
+func PTRRecord(addr netip.Addr, target string, ttl uint32) (dns.RR, error) {
+	name, err := dns.ReverseAddr(addr.String())
+	if err != nil {
+		return nil, err
+	}
+	return &dns.PTR{Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: ttl}, Ptr: target}, nil
+}
+
+// Zone describes a zone generated from the inventory
+type Zone struct {
+	// Name of the zone, e.g. example.internal, or a reverse zone like
+	// 10.in-addr.arpa
+	Name string
+	// Domain the hostnames in PTR records are qualified with, Name when
+	// empty as for forward zones
+	Domain string
+	// NS are the zone's name servers, ns1.<Domain> when empty
+	NS []string
+	// Mbox is the SOA contact, hostmaster.<Domain> when empty
+	Mbox   string
+	Serial uint32
+	TTL    uint32
+}
+
+func (z Zone) origin() string {
+	return dns.Fqdn(strings.ToLower(z.Name))
+}
+
+func (z Zone) domain() string {
+	if z.Domain == "" {
+		return z.origin()
+	}
+	return dns.Fqdn(strings.ToLower(z.Domain))
+}
+
+// SOA is the zone's start of authority
+func (z Zone) SOA() *dns.SOA {
+	ns := z.nameServers()
+	mbox := z.Mbox
+	if mbox == "" {
+		mbox = "hostmaster." + z.domain()
+	}
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: z.origin(), Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: z.TTL},
+		Ns:      ns[0],
+		Mbox:    dns.Fqdn(strings.Replace(mbox, "@", ".", 1)),
+		Serial:  z.Serial,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  604800,
+		Minttl:  z.TTL,
+	}
+}
+
+func (z Zone) nameServers() []string {
+	if len(z.NS) == 0 {
+		return []string{"ns1." + z.domain()}
+	}
+	ns := make([]string, len(z.NS))
+	for i, n := range z.NS {
+		ns[i] = dns.Fqdn(n)
+	}
+	return ns
+}
+
+// Records are the records for vms in the zone: A and AAAA records for their
+// addresses in a forward zone, PTR records for the addresses within a
+// reverse zone. Addresses that are not valid are skipped.
+func (z Zone) Records(vms []*pbv2.Virtualmachine) []dns.RR {
+	reverse := IsReverseZone(z.Name)
+
+	var rrs []dns.RR
+	for _, vm := range vms {
+		for _, a := range vm.Addresses {
+			addr, err := netip.ParseAddr(a)
+			if err != nil {
+				continue
+			}
+			if !reverse {
+				rrs = append(rrs, AddressRecord(FQDN(vm.Hostname, z.Name), addr, z.TTL))
+				continue
+			}
+			rr, err := PTRRecord(addr, FQDN(vm.Hostname, z.domain()), z.TTL)
+			if err == nil && dns.IsSubDomain(z.origin(), rr.Header().Name) {
+				rrs = append(rrs, rr)
+			}
+		}
+	}
+	sort.SliceStable(rrs, func(i, j int) bool {
+		return rrs[i].Header().Name < rrs[j].Header().Name
+	})
+	return rrs
+}
+
+// Write writes the zone for vms as a BIND zone file
+func (z Zone) Write(w io.Writer, vms []*pbv2.Virtualmachine) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "; generated by sreapi, do not edit\n")
+	fmt.Fprintf(&b, "$ORIGIN %s\n$TTL %d\n", z.origin(), z.TTL)
+	fmt.Fprintln(&b, z.SOA().String())
+	for _, ns := range z.nameServers() {
+		fmt.Fprintln(&b, (&dns.NS{Hdr: dns.RR_Header{Name: z.origin(), Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: z.TTL}, Ns: ns}).String())
+	}
+	for _, rr := range z.Records(vms) {
+		fmt.Fprintln(&b, rr.String())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
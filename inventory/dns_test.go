@@ -0,0 +1,30 @@
+package inventory
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestReverseZone(t *testing.T) {
+	tests := []struct {
+		cidr, want string
+	}{
+		{"10.2.3.0/24", "3.2.10.in-addr.arpa."},
+		{"10.2.0.0/16", "2.10.in-addr.arpa."},
+		{"10.2.4.0/22", "2.10.in-addr.arpa."},
+		{"10.2.3.128/25", "3.2.10.in-addr.arpa."},
+		{"10.2.3.4/32", "4.3.2.10.in-addr.arpa."},
+		{"10.0.0.0/8", "10.in-addr.arpa."},
+		{"0.0.0.0/0", "in-addr.arpa."},
+		{"2001:db8::/32", "8.b.d.0.1.0.0.2.ip6.arpa."},
+		{"2001:db8:1::/48", "1.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."},
+		{"2001:db8:4000::/34", "8.b.d.0.1.0.0.2.ip6.arpa."},
+		{"2001:db8:4000::/36", "4.8.b.d.0.1.0.0.2.ip6.arpa."},
+		{"::/0", "ip6.arpa."},
+	}
+	for _, tt := range tests {
+		if got := ReverseZone(netip.MustParsePrefix(tt.cidr)); got != tt.want {
+			t.Errorf("ReverseZone(%s) = %q, want %q", tt.cidr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// ENCRule is what a project, role or label adds to a node's classification
+type ENCRule struct {
+	Environment string                 `json:"environment,omitempty"`
+	Classes     []string               `json:"classes,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// ENCMapping maps vms to their puppet or salt classification. The top level
+// rule applies to every vm, then the rules for its project, its role and its
+// labels, keyed by name=value or by name for any value. Later rules add
+// classes, override parameters and replace the environment.
+type ENCMapping struct {
+	ENCRule
+	Projects map[string]ENCRule `json:"projects,omitempty"`
+	Roles    map[string]ENCRule `json:"roles,omitempty"`
+	Labels   map[string]ENCRule `json:"labels,omitempty"`
+}
+
+// ENCNode is a node's classification as an external node classifier prints
+// it
+type ENCNode struct {
+	Classes     []string               `json:"classes"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Environment string                 `json:"environment,omitempty"`
+}
+
+// AddClasses adds the classes node does not have yet, e.g. those of the
+// vm's role in the roles catalog
+func (n *ENCNode) AddClasses(classes ...string) {
+	for _, class := range classes {
+		if !slices.Contains(n.Classes, class) {
+			n.Classes = append(n.Classes, class)
+		}
+	}
+}
+
+// LoadENCMapping reads a mapping from a YAML or JSON file, rejecting fields
+// it does not know
+func LoadENCMapping(path string) (*ENCMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &ENCMapping{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Classify works out vm's classification. The parameters always hold
+// sreapi_hostname, sreapi_project, sreapi_role and sreapi_labels.
+func (m *ENCMapping) Classify(vm *pbv2.Virtualmachine) *ENCNode {
+	node := &ENCNode{Classes: []string{}, Parameters: map[string]interface{}{}}
+	seen := map[string]bool{}
+	apply := func(r ENCRule) {
+		for _, class := range r.Classes {
+			if !seen[class] {
+				seen[class] = true
+				node.Classes = append(node.Classes, class)
+			}
+		}
+		for k, v := range r.Parameters {
+			node.Parameters[k] = v
+		}
+		if r.Environment != "" {
+			node.Environment = r.Environment
+		}
+	}
+
+	apply(m.ENCRule)
+	apply(m.Projects[vm.Project])
+	apply(m.Roles[vm.Role])
+	names := make([]string, 0, len(vm.Labels))
+	for name := range vm.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		apply(m.Labels[name])
+		apply(m.Labels[name+"="+vm.Labels[name]])
+	}
+
+	for k, v := range HostVars(vm) {
+		node.Parameters[k] = v
+	}
+	return node
+}
@@ -0,0 +1,130 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const encMapping = `
+environment: production
+classes: [base]
+parameters: {ntp: pool.ntp.org, team: ops}
+projects:
+  billing:
+    classes: [base, payments]
+    parameters: {team: payments}
+roles:
+  web:
+    classes: [nginx]
+labels:
+  env:
+    classes: [monitored]
+  env=staging:
+    environment: staging
+    parameters: {ntp: ntp.staging}
+`
+
+func writeMapping(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "enc.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadENCMapping(t *testing.T) {
+	m, err := LoadENCMapping(writeMapping(t, encMapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Environment != "production" || len(m.Projects) != 1 || len(m.Roles) != 1 || len(m.Labels) != 2 {
+		t.Errorf("LoadENCMapping = %+v", m)
+	}
+
+	for _, data := range []string{"clases: [base]\n", "projects: [billing]\n", "environment: [\n"} {
+		if _, err := LoadENCMapping(writeMapping(t, data)); err == nil {
+			t.Errorf("LoadENCMapping(%q) did not fail", data)
+		}
+	}
+	if _, err := LoadENCMapping(filepath.Join(t.TempDir(), "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("LoadENCMapping of a missing file error = %v", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	m, err := LoadENCMapping(writeMapping(t, encMapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		vm          *pbv2.Virtualmachine
+		classes     []string
+		environment string
+		team, ntp   string
+	}{
+		{
+			&pbv2.Virtualmachine{Hostname: "db01", Project: "shop", Role: "db"},
+			[]string{"base"}, "production", "ops", "pool.ntp.org",
+		},
+		{
+			&pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web", Labels: map[string]string{"env": "prod"}},
+			[]string{"base", "payments", "nginx", "monitored"}, "production", "payments", "pool.ntp.org",
+		},
+		{
+			&pbv2.Virtualmachine{Hostname: "web02", Project: "billing", Role: "web", Labels: map[string]string{"env": "staging"}},
+			[]string{"base", "payments", "nginx", "monitored"}, "staging", "payments", "ntp.staging",
+		},
+	}
+	for _, tt := range tests {
+		node := m.Classify(tt.vm)
+		if !reflect.DeepEqual(node.Classes, tt.classes) {
+			t.Errorf("%s: classes = %q, want %q", tt.vm.Hostname, node.Classes, tt.classes)
+		}
+		if node.Environment != tt.environment {
+			t.Errorf("%s: environment = %q, want %q", tt.vm.Hostname, node.Environment, tt.environment)
+		}
+		if node.Parameters["team"] != tt.team || node.Parameters["ntp"] != tt.ntp {
+			t.Errorf("%s: parameters = %v, want team %s and ntp %s", tt.vm.Hostname, node.Parameters, tt.team, tt.ntp)
+		}
+		if node.Parameters["sreapi_hostname"] != tt.vm.Hostname || node.Parameters["sreapi_role"] != tt.vm.Role {
+			t.Errorf("%s: parameters = %v, want the sreapi hostvars", tt.vm.Hostname, node.Parameters)
+		}
+	}
+}
+
+func TestClassifyDoesNotChangeMapping(t *testing.T) {
+	m, err := LoadENCMapping(writeMapping(t, encMapping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := m.Classify(&pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web"})
+	node.AddClasses("nginx", "haproxy")
+	node.Parameters["team"] = "changed"
+	if want := []string{"base", "payments", "nginx", "haproxy"}; !reflect.DeepEqual(node.Classes, want) {
+		t.Errorf("AddClasses = %q, want %q", node.Classes, want)
+	}
+
+	again := m.Classify(&pbv2.Virtualmachine{Hostname: "web01", Project: "billing", Role: "web"})
+	if !reflect.DeepEqual(again.Classes, []string{"base", "payments", "nginx"}) || again.Parameters["team"] != "payments" {
+		t.Errorf("second Classify = %+v, the first node changed the mapping", again)
+	}
+}
+
+func TestENCNodeYAML(t *testing.T) {
+	// puppet wants classes and parameters even when there are none
+	node := (&ENCMapping{}).Classify(&pbv2.Virtualmachine{Hostname: "web01"})
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "classes: []\nparameters:\n") || strings.Contains(string(out), "environment") {
+		t.Errorf("node YAML =\n%s", out)
+	}
+}
@@ -0,0 +1,44 @@
+package inventory
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+// DefaultScrapePort is the port targets are scraped on when none is given,
+// node_exporter's
+const DefaultScrapePort = 9100
+
+var labelInvalid = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// TargetGroup is a group of targets as prometheus http_sd and file_sd read
+// them
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// PrometheusTargets builds a target group per vm, scraped on port. The vm's
+// project and role become the project and role target labels, and its labels
+// label_<name> with the name made a valid prometheus label name.
+func PrometheusTargets(vms []*pbv2.Virtualmachine, port int) []TargetGroup {
+	groups := make([]TargetGroup, 0, len(vms))
+	for _, vm := range vms {
+		labels := map[string]string{
+			"hostname": vm.Hostname,
+			"project":  vm.Project,
+			"role":     vm.Role,
+		}
+		for name, value := range vm.Labels {
+			labels["label_"+labelInvalid.ReplaceAllString(name, "_")] = value
+		}
+		groups = append(groups, TargetGroup{
+			Targets: []string{net.JoinHostPort(vm.Hostname, strconv.Itoa(port))},
+			Labels:  labels,
+		})
+	}
+	return groups
+}
@@ -0,0 +1,47 @@
+package inventory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestPrometheusTargets(t *testing.T) {
+	groups := PrometheusTargets([]*pbv2.Virtualmachine{
+		{Hostname: "web01", Project: "billing", Role: "web", Labels: map[string]string{"env": "prod", "k8s.io/zone": "eu-1"}},
+		{Hostname: "db01", Project: "billing", Role: "db"},
+	}, 9100)
+
+	want := []TargetGroup{
+		{
+			Targets: []string{"web01:9100"},
+			Labels: map[string]string{
+				"hostname":          "web01",
+				"project":           "billing",
+				"role":              "web",
+				"label_env":         "prod",
+				"label_k8s_io_zone": "eu-1",
+			},
+		},
+		{
+			Targets: []string{"db01:9100"},
+			Labels:  map[string]string{"hostname": "db01", "project": "billing", "role": "db"},
+		},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("PrometheusTargets = %+v, want %+v", groups, want)
+	}
+}
+
+func TestPrometheusTargetsEmpty(t *testing.T) {
+	// prometheus wants an empty list, not null, when there is nothing to scrape
+	data, err := json.Marshal(PrometheusTargets(nil, DefaultScrapePort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[]" {
+		t.Errorf("PrometheusTargets(nil) = %s, want []", data)
+	}
+}
@@ -0,0 +1,144 @@
+package inventory
+
+import (
+	"fmt"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+// Managed block markers, the lines between them belong to sreapi
+const (
+	BeginMarker = "# BEGIN sreapi managed block"
+	EndMarker   = "# END sreapi managed block"
+)
+
+// ReplaceBlock returns text with the managed block replaced by block, or
+// with block added at the end when text has none. The rest of text is left
+// as it is.
+func ReplaceBlock(text, block string) (string, error) {
+	if block != "" && !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+	managed := BeginMarker + "\n" + block + EndMarker + "\n"
+
+	begin := strings.Index(text, BeginMarker+"\n")
+	if begin < 0 {
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		if text != "" {
+			text += "\n"
+		}
+		return text + managed, nil
+	}
+	if begin > 0 && text[begin-1] != '\n' {
+		return "", fmt.Errorf("%q is not at the start of a line", BeginMarker)
+	}
+	n := strings.Index(text[begin:], EndMarker)
+	if n < 0 {
+		return "", fmt.Errorf("%q without %q", BeginMarker, EndMarker)
+	}
+	end := begin + n + len(EndMarker)
+	if end < len(text) && text[end] == '\n' {
+		end++
+	}
+	return text[:begin] + managed + text[end:], nil
+}
+
+// Hosts renders /etc/hosts lines for the addresses of vms. Each address maps
+// to the vm's name in domain, then its hostname when that differs.
+func Hosts(vms []*pbv2.Virtualmachine, domain string) string {
+	var b strings.Builder
+	for _, vm := range vms {
+		names := []string{strings.TrimSuffix(FQDN(vm.Hostname, domain), ".")}
+		if names[0] != vm.Hostname {
+			names = append(names, vm.Hostname)
+		}
+		for _, addr := range vm.Addresses {
+			fmt.Fprintf(&b, "%s\t%s\n", addr, strings.Join(names, " "))
+		}
+	}
+	return b.String()
+}
+
+// SSHSettings are the ssh options for a group of vms, empty fields are left
+// out
+type SSHSettings struct {
+	User         string `mapstructure:"user"`
+	Port         int    `mapstructure:"port"`
+	IdentityFile string `mapstructure:"identity_file"`
+	// Bastion is the host to jump through, as ProxyJump takes it
+	Bastion string `mapstructure:"bastion"`
+}
+
+// merge returns s with the fields set in o replacing its own
+func (s SSHSettings) merge(o SSHSettings) SSHSettings {
+	if o.User != "" {
+		s.User = o.User
+	}
+	if o.Port != 0 {
+		s.Port = o.Port
+	}
+	if o.IdentityFile != "" {
+		s.IdentityFile = o.IdentityFile
+	}
+	if o.Bastion != "" {
+		s.Bastion = o.Bastion
+	}
+	return s
+}
+
+// SSHConfig holds ssh settings for every vm, per project and per role. Role
+// settings win over project settings, which win over the defaults.
+type SSHConfig struct {
+	SSHSettings `mapstructure:",squash"`
+	Projects    map[string]SSHSettings `mapstructure:"projects"`
+	Roles       map[string]SSHSettings `mapstructure:"roles"`
+}
+
+// For returns the settings for vm
+func (c SSHConfig) For(vm *pbv2.Virtualmachine) SSHSettings {
+	return c.SSHSettings.merge(lookup(c.Projects, vm.Project)).merge(lookup(c.Roles, vm.Role))
+}
+
+// lookup finds name in m, or its lower case form as viper keeps keys
+func lookup(m map[string]SSHSettings, name string) SSHSettings {
+	if s, ok := m[name]; ok {
+		return s
+	}
+	return m[strings.ToLower(name)]
+}
+
+// SSH renders a Host entry per vm for ~/.ssh/config. Vms connect to their
+// first address when they have one, otherwise to their name in domain.
+func SSH(vms []*pbv2.Virtualmachine, domain string, cfg SSHConfig) string {
+	var b strings.Builder
+	for i, vm := range vms {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		hostname := strings.TrimSuffix(FQDN(vm.Hostname, domain), ".")
+		if len(vm.Addresses) > 0 {
+			hostname = vm.Addresses[0]
+		}
+		fmt.Fprintf(&b, "Host %s\n", vm.Hostname)
+		fmt.Fprintf(&b, "    HostName %s\n", hostname)
+
+		s := cfg.For(vm)
+		if s.User != "" {
+			fmt.Fprintf(&b, "    User %s\n", s.User)
+		}
+		if s.Port != 0 {
+			fmt.Fprintf(&b, "    Port %d\n", s.Port)
+		}
+		if s.IdentityFile != "" {
+			fmt.Fprintf(&b, "    IdentityFile %s\n", s.IdentityFile)
+			fmt.Fprintf(&b, "    IdentitiesOnly yes\n")
+		}
+		if s.Bastion != "" {
+			fmt.Fprintf(&b, "    ProxyJump %s\n", s.Bastion)
+		}
+	}
+	return b.String()
+}
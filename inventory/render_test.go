@@ -0,0 +1,128 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+func TestReplaceBlock(t *testing.T) {
+	const block = "10.0.0.5\tweb01\n"
+	managed := BeginMarker + "\n" + block + EndMarker + "\n"
+	tests := []struct {
+		name, text, want string
+		err              string
+	}{
+		{"empty file", "", managed, ""},
+		{"no block", "127.0.0.1\tlocalhost\n", "127.0.0.1\tlocalhost\n\n" + managed, ""},
+		{"no trailing newline", "127.0.0.1\tlocalhost", "127.0.0.1\tlocalhost\n\n" + managed, ""},
+		{"block in the middle",
+			"127.0.0.1\tlocalhost\n" + BeginMarker + "\n10.0.0.9\told\n" + EndMarker + "\n::1\tlocalhost\n",
+			"127.0.0.1\tlocalhost\n" + managed + "::1\tlocalhost\n", ""},
+		{"block at the end without a newline",
+			"127.0.0.1\tlocalhost\n" + BeginMarker + "\n10.0.0.9\told\n" + EndMarker,
+			"127.0.0.1\tlocalhost\n" + managed, ""},
+		{"empty block", BeginMarker + "\n" + EndMarker + "\n", managed, ""},
+		{"no end marker", "127.0.0.1\tlocalhost\n" + BeginMarker + "\n10.0.0.9\told\n", "", "without"},
+		{"begin marker inside a line", "127.0.0.1\tlocalhost " + BeginMarker + "\n" + EndMarker + "\n", "", "not at the start of a line"},
+	}
+	for _, tt := range tests {
+		got, err := ReplaceBlock(tt.text, block)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: ReplaceBlock error = %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ReplaceBlock = %q, %v, want %q", tt.name, got, err, tt.want)
+			continue
+		}
+		// Rendering again changes nothing, render relies on this to leave
+		// files that are up to date alone
+		if again, err := ReplaceBlock(got, block); err != nil || again != got {
+			t.Errorf("%s: ReplaceBlock again = %q, %v, want %q", tt.name, again, err, got)
+		}
+	}
+
+	got, err := ReplaceBlock("", "10.0.0.5\tweb01")
+	if err != nil || got != managed {
+		t.Errorf("ReplaceBlock of a block without a newline = %q, %v, want %q", got, err, managed)
+	}
+	got, err = ReplaceBlock(managed, "")
+	if want := BeginMarker + "\n" + EndMarker + "\n"; err != nil || got != want {
+		t.Errorf("ReplaceBlock with an empty block = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestHosts(t *testing.T) {
+	vms := []*pbv2.Virtualmachine{
+		{Hostname: "web01", Addresses: []string{"10.0.0.5", "2001:db8::5"}},
+		{Hostname: "db01.example.com"},
+		{Hostname: "db02.example.com", Addresses: []string{"10.0.0.7"}},
+	}
+	want := "10.0.0.5\tweb01.example.com web01\n2001:db8::5\tweb01.example.com web01\n10.0.0.7\tdb02.example.com\n"
+	if got := Hosts(vms, "example.com"); got != want {
+		t.Errorf("Hosts = %q, want %q", got, want)
+	}
+	if got, want := Hosts(vms[:1], ""), "10.0.0.5\tweb01\n2001:db8::5\tweb01\n"; got != want {
+		t.Errorf("Hosts without a domain = %q, want %q", got, want)
+	}
+}
+
+func TestSSHConfigFor(t *testing.T) {
+	cfg := SSHConfig{
+		SSHSettings: SSHSettings{User: "admin", Port: 22, IdentityFile: "~/.ssh/id_ed25519"},
+		Projects: map[string]SSHSettings{
+			"billing": {User: "billing", Bastion: "bastion.billing"},
+		},
+		Roles: map[string]SSHSettings{
+			"db":    {User: "postgres", Port: 2222},
+			"Cache": {Port: 6022},
+		},
+	}
+	tests := []struct {
+		name          string
+		project, role string
+		want          SSHSettings
+	}{
+		{"defaults", "shop", "web", SSHSettings{User: "admin", Port: 22, IdentityFile: "~/.ssh/id_ed25519"}},
+		{"project over defaults", "billing", "web", SSHSettings{User: "billing", Port: 22, IdentityFile: "~/.ssh/id_ed25519", Bastion: "bastion.billing"}},
+		{"role over project", "billing", "db", SSHSettings{User: "postgres", Port: 2222, IdentityFile: "~/.ssh/id_ed25519", Bastion: "bastion.billing"}},
+		{"lower case key as viper keeps them", "Billing", "DB", SSHSettings{User: "postgres", Port: 2222, IdentityFile: "~/.ssh/id_ed25519", Bastion: "bastion.billing"}},
+		{"exact key first", "shop", "Cache", SSHSettings{User: "admin", Port: 6022, IdentityFile: "~/.ssh/id_ed25519"}},
+	}
+	for _, tt := range tests {
+		if got := cfg.For(&pbv2.Virtualmachine{Hostname: "vm01", Project: tt.project, Role: tt.role}); got != tt.want {
+			t.Errorf("%s: For(%s, %s) = %+v, want %+v", tt.name, tt.project, tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestSSH(t *testing.T) {
+	vms := []*pbv2.Virtualmachine{
+		{Hostname: "web01", Project: "shop", Role: "web", Addresses: []string{"10.0.0.5", "10.0.0.6"}},
+		{Hostname: "db01", Project: "billing", Role: "db"},
+	}
+	cfg := SSHConfig{
+		SSHSettings: SSHSettings{User: "admin"},
+		Projects:    map[string]SSHSettings{"billing": {Bastion: "bastion.billing", IdentityFile: "~/.ssh/billing"}},
+		Roles:       map[string]SSHSettings{"db": {Port: 2222}},
+	}
+	want := `Host web01
+    HostName 10.0.0.5
+    User admin
+
+Host db01
+    HostName db01.example.com
+    User admin
+    Port 2222
+    IdentityFile ~/.ssh/billing
+    IdentitiesOnly yes
+    ProxyJump bastion.billing
+`
+	if got := SSH(vms, "example.com", cfg); got != want {
+		t.Errorf("SSH =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+// Package openapi serves the OpenAPI document generated from the protobuf
+// definitions by gen_proto.sh, and a page to explore it with swagger-ui.
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"net/http"
+)
+
+//go:embed protobuf/vm.swagger.json protobuf/v2/vm.swagger.json protobuf/v2/ipam.swagger.json protobuf/v2/project.swagger.json protobuf/v2/role.swagger.json
+var specs embed.FS
+
+//go:embed index.html
+var indexHTML []byte
+
+// specFiles are merged into one document in this order
+var specFiles = []string{
+	"protobuf/vm.swagger.json",
+	"protobuf/v2/vm.swagger.json",
+	"protobuf/v2/ipam.swagger.json",
+	"protobuf/v2/project.swagger.json",
+	"protobuf/v2/role.swagger.json",
+}
+
+// Spec returns the swagger 2.0 document for every api version
+func Spec() ([]byte, error) {
+	merged := map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":   "sreapi",
+			"version": "v2",
+		},
+		"consumes": []string{"application/json"},
+		"produces": []string{"application/json"},
+	}
+	paths := map[string]interface{}{}
+	definitions := map[string]interface{}{}
+
+	for _, name := range specFiles {
+		b, err := specs.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc struct {
+			Paths       map[string]interface{} `json:"paths"`
+			Definitions map[string]interface{} `json:"definitions"`
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+		for k, v := range doc.Paths {
+			paths[k] = v
+		}
+		for k, v := range doc.Definitions {
+			definitions[k] = v
+		}
+	}
+
+	merged["paths"] = paths
+	merged["definitions"] = definitions
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// SpecHandler serves the document returned by Spec
+func SpecHandler() http.Handler {
+	spec, err := Spec()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+}
+
+// UIHandler serves a swagger-ui page pointed at /openapi.json
+func UIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexHTML)
+	})
+}
@@ -0,0 +1,1761 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: protobuf/v2/vm.proto
+
+package sreapiv2
+
+import (
+	context "context"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Sizing is the resources of a vm, unset fields are 0
+type Sizing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cpus     int32 `protobuf:"varint,1,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	MemoryMb int32 `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	DiskGb   int32 `protobuf:"varint,3,opt,name=disk_gb,json=diskGb,proto3" json:"disk_gb,omitempty"`
+}
+
+func (x *Sizing) Reset() {
+	*x = Sizing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sizing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sizing) ProtoMessage() {}
+
+func (x *Sizing) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sizing.ProtoReflect.Descriptor instead.
+func (*Sizing) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Sizing) GetCpus() int32 {
+	if x != nil {
+		return x.Cpus
+	}
+	return 0
+}
+
+func (x *Sizing) GetMemoryMb() int32 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *Sizing) GetDiskGb() int32 {
+	if x != nil {
+		return x.DiskGb
+	}
+	return 0
+}
+
+type Virtualmachine struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Project  string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	Role     string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	// free form key/value pairs, e.g. env=prod, used to group vms
+	Labels map[string]string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// IPv4 and IPv6 addresses the hostname resolves to
+	Addresses []string `protobuf:"bytes,5,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// the role's default sizing when not given at create
+	Sizing *Sizing `protobuf:"bytes,6,opt,name=sizing,proto3" json:"sizing,omitempty"`
+}
+
+func (x *Virtualmachine) Reset() {
+	*x = Virtualmachine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Virtualmachine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Virtualmachine) ProtoMessage() {}
+
+func (x *Virtualmachine) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Virtualmachine.ProtoReflect.Descriptor instead.
+func (*Virtualmachine) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Virtualmachine) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Virtualmachine) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *Virtualmachine) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Virtualmachine) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Virtualmachine) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Virtualmachine) GetSizing() *Sizing {
+	if x != nil {
+		return x.Sizing
+	}
+	return nil
+}
+
+type ListVirtualmachinesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Project string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Role    string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// vms per page, at most 1000. All vms are returned at once when 0.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from the previous page
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListVirtualmachinesRequest) Reset() {
+	*x = ListVirtualmachinesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVirtualmachinesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVirtualmachinesRequest) ProtoMessage() {}
+
+func (x *ListVirtualmachinesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVirtualmachinesRequest.ProtoReflect.Descriptor instead.
+func (*ListVirtualmachinesRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListVirtualmachinesRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ListVirtualmachinesRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListVirtualmachinesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListVirtualmachinesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListVirtualmachinesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vms []*Virtualmachine `protobuf:"bytes,1,rep,name=vms,proto3" json:"vms,omitempty"`
+	// page_token for the next page, empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ListVirtualmachinesResponse) Reset() {
+	*x = ListVirtualmachinesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVirtualmachinesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVirtualmachinesResponse) ProtoMessage() {}
+
+func (x *ListVirtualmachinesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVirtualmachinesResponse.ProtoReflect.Descriptor instead.
+func (*ListVirtualmachinesResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListVirtualmachinesResponse) GetVms() []*Virtualmachine {
+	if x != nil {
+		return x.Vms
+	}
+	return nil
+}
+
+func (x *ListVirtualmachinesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetVirtualmachineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+}
+
+func (x *GetVirtualmachineRequest) Reset() {
+	*x = GetVirtualmachineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVirtualmachineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVirtualmachineRequest) ProtoMessage() {}
+
+func (x *GetVirtualmachineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVirtualmachineRequest.ProtoReflect.Descriptor instead.
+func (*GetVirtualmachineRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVirtualmachineRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+type CreateVirtualmachineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vm *Virtualmachine `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
+	// cidr of a subnet to give the vm its next free address from
+	AllocateFrom string `protobuf:"bytes,2,opt,name=allocate_from,json=allocateFrom,proto3" json:"allocate_from,omitempty"`
+	// name the vm from its project's naming template, vm.hostname must be empty
+	AutoHostname bool `protobuf:"varint,3,opt,name=auto_hostname,json=autoHostname,proto3" json:"auto_hostname,omitempty"`
+	// token AllocateHostname returned with vm.hostname, needed to create the
+	// vm with a hostname it reserved
+	ReservationToken string `protobuf:"bytes,4,opt,name=reservation_token,json=reservationToken,proto3" json:"reservation_token,omitempty"`
+}
+
+func (x *CreateVirtualmachineRequest) Reset() {
+	*x = CreateVirtualmachineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateVirtualmachineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVirtualmachineRequest) ProtoMessage() {}
+
+func (x *CreateVirtualmachineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVirtualmachineRequest.ProtoReflect.Descriptor instead.
+func (*CreateVirtualmachineRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateVirtualmachineRequest) GetVm() *Virtualmachine {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+func (x *CreateVirtualmachineRequest) GetAllocateFrom() string {
+	if x != nil {
+		return x.AllocateFrom
+	}
+	return ""
+}
+
+func (x *CreateVirtualmachineRequest) GetAutoHostname() bool {
+	if x != nil {
+		return x.AutoHostname
+	}
+	return false
+}
+
+func (x *CreateVirtualmachineRequest) GetReservationToken() string {
+	if x != nil {
+		return x.ReservationToken
+	}
+	return ""
+}
+
+type UpdateVirtualmachineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hostname of the vm to update, vm.hostname may differ to rename it
+	Hostname string          `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Vm       *Virtualmachine `protobuf:"bytes,2,opt,name=vm,proto3" json:"vm,omitempty"`
+	// fields of vm to update, all fields are replaced when empty
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+}
+
+func (x *UpdateVirtualmachineRequest) Reset() {
+	*x = UpdateVirtualmachineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateVirtualmachineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVirtualmachineRequest) ProtoMessage() {}
+
+func (x *UpdateVirtualmachineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVirtualmachineRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVirtualmachineRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateVirtualmachineRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *UpdateVirtualmachineRequest) GetVm() *Virtualmachine {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+func (x *UpdateVirtualmachineRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DeleteVirtualmachineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+}
+
+func (x *DeleteVirtualmachineRequest) Reset() {
+	*x = DeleteVirtualmachineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVirtualmachineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVirtualmachineRequest) ProtoMessage() {}
+
+func (x *DeleteVirtualmachineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVirtualmachineRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVirtualmachineRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteVirtualmachineRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+type BatchCreateVirtualmachinesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// vms to create in one transaction, at most 1000
+	Vms []*Virtualmachine `protobuf:"bytes,1,rep,name=vms,proto3" json:"vms,omitempty"`
+	// replace vms that already exist instead of failing with ALREADY_EXISTS
+	Upsert bool `protobuf:"varint,2,opt,name=upsert,proto3" json:"upsert,omitempty"`
+}
+
+func (x *BatchCreateVirtualmachinesRequest) Reset() {
+	*x = BatchCreateVirtualmachinesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateVirtualmachinesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateVirtualmachinesRequest) ProtoMessage() {}
+
+func (x *BatchCreateVirtualmachinesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateVirtualmachinesRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateVirtualmachinesRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchCreateVirtualmachinesRequest) GetVms() []*Virtualmachine {
+	if x != nil {
+		return x.Vms
+	}
+	return nil
+}
+
+func (x *BatchCreateVirtualmachinesRequest) GetUpsert() bool {
+	if x != nil {
+		return x.Upsert
+	}
+	return false
+}
+
+type BatchCreateVirtualmachinesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vms []*Virtualmachine `protobuf:"bytes,1,rep,name=vms,proto3" json:"vms,omitempty"`
+}
+
+func (x *BatchCreateVirtualmachinesResponse) Reset() {
+	*x = BatchCreateVirtualmachinesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchCreateVirtualmachinesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateVirtualmachinesResponse) ProtoMessage() {}
+
+func (x *BatchCreateVirtualmachinesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateVirtualmachinesResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateVirtualmachinesResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchCreateVirtualmachinesResponse) GetVms() []*Virtualmachine {
+	if x != nil {
+		return x.Vms
+	}
+	return nil
+}
+
+// NamingTemplate is how the vms of a project are named. {project} and {role}
+// are replaced by the vm's project and role, {nn} by the lowest free number
+// padded to as many digits as there are n's, e.g. {project}-{role}-{nn}.
+type NamingTemplate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Project  string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Template string `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+}
+
+func (x *NamingTemplate) Reset() {
+	*x = NamingTemplate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NamingTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NamingTemplate) ProtoMessage() {}
+
+func (x *NamingTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NamingTemplate.ProtoReflect.Descriptor instead.
+func (*NamingTemplate) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NamingTemplate) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *NamingTemplate) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+type GetNamingTemplateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Project string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+}
+
+func (x *GetNamingTemplateRequest) Reset() {
+	*x = GetNamingTemplateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNamingTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNamingTemplateRequest) ProtoMessage() {}
+
+func (x *GetNamingTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNamingTemplateRequest.ProtoReflect.Descriptor instead.
+func (*GetNamingTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetNamingTemplateRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+type SetNamingTemplateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NamingTemplate *NamingTemplate `protobuf:"bytes,1,opt,name=naming_template,json=namingTemplate,proto3" json:"naming_template,omitempty"`
+}
+
+func (x *SetNamingTemplateRequest) Reset() {
+	*x = SetNamingTemplateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNamingTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNamingTemplateRequest) ProtoMessage() {}
+
+func (x *SetNamingTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNamingTemplateRequest.ProtoReflect.Descriptor instead.
+func (*SetNamingTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetNamingTemplateRequest) GetNamingTemplate() *NamingTemplate {
+	if x != nil {
+		return x.NamingTemplate
+	}
+	return nil
+}
+
+type AllocateHostnameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Project string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Role    string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *AllocateHostnameRequest) Reset() {
+	*x = AllocateHostnameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllocateHostnameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocateHostnameRequest) ProtoMessage() {}
+
+func (x *AllocateHostnameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocateHostnameRequest.ProtoReflect.Descriptor instead.
+func (*AllocateHostnameRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AllocateHostnameRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *AllocateHostnameRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type AllocateHostnameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hostname reserved for a vm created within the hour
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// pass as the reservation_token of Create to use the hostname
+	ReservationToken string `protobuf:"bytes,2,opt,name=reservation_token,json=reservationToken,proto3" json:"reservation_token,omitempty"`
+}
+
+func (x *AllocateHostnameResponse) Reset() {
+	*x = AllocateHostnameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_v2_vm_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllocateHostnameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocateHostnameResponse) ProtoMessage() {}
+
+func (x *AllocateHostnameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_v2_vm_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocateHostnameResponse.ProtoReflect.Descriptor instead.
+func (*AllocateHostnameResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_v2_vm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AllocateHostnameResponse) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *AllocateHostnameResponse) GetReservationToken() string {
+	if x != nil {
+		return x.ReservationToken
+	}
+	return ""
+}
+
+var File_protobuf_v2_vm_proto protoreflect.FileDescriptor
+
+var file_protobuf_v2_vm_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76,
+	0x32, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x52,
+	0x0a, 0x06, 0x53, 0x69, 0x7a, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x70, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x70, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x09,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x69, 0x73,
+	0x6b, 0x5f, 0x67, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x69, 0x73, 0x6b,
+	0x47, 0x62, 0x22, 0x9d, 0x02, 0x0a, 0x0e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72,
+	0x6f, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12,
+	0x3d, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x06,
+	0x73, 0x69, 0x7a, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x73,
+	0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x69, 0x7a, 0x69, 0x6e, 0x67, 0x52,
+	0x06, 0x73, 0x69, 0x7a, 0x69, 0x6e, 0x67, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x86, 0x01, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72,
+	0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x72, 0x0a, 0x1b, 0x4c,
+	0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x03, 0x76, 0x6d,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69,
+	0x6e, 0x65, 0x52, 0x03, 0x76, 0x6d, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x36, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xbf, 0x01, 0x0a, 0x1b, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x02, 0x76, 0x6d, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e,
+	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x02,
+	0x76, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x75, 0x74, 0x6f, 0x5f,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x61, 0x75, 0x74, 0x6f, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x11,
+	0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xa1, 0x01, 0x0a, 0x1b, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69,
+	0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73,
+	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73,
+	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x02, 0x76, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x02, 0x76, 0x6d,
+	0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73,
+	0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x39, 0x0a,
+	0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x68, 0x0a, 0x21, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a,
+	0x03, 0x76, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65,
+	0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x03, 0x76, 0x6d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70,
+	0x73, 0x65, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x70, 0x73, 0x65,
+	0x72, 0x74, 0x22, 0x51, 0x0a, 0x22, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x03, 0x76, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76,
+	0x32, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x52, 0x03, 0x76, 0x6d, 0x73, 0x22, 0x46, 0x0a, 0x0e, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x34, 0x0a,
+	0x18, 0x47, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x22, 0x5e, 0x0a, 0x18, 0x53, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x42, 0x0a, 0x0f, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x52, 0x0e, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x22, 0x47, 0x0a, 0x17, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x48,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x63, 0x0a, 0x18,
+	0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x32, 0x86, 0x09, 0x0a, 0x0f, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x84, 0x01, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x25,
+	0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76,
+	0x32, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x27, 0x5a, 0x1c, 0x12, 0x1a, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x73, 0x2f, 0x7b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x7d, 0x2f,
+	0x76, 0x6d, 0x73, 0x12, 0x07, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d, 0x73, 0x12, 0x61, 0x0a, 0x03,
+	0x47, 0x65, 0x74, 0x12, 0x23, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e,
+	0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x12, 0x12, 0x2f, 0x76, 0x32,
+	0x2f, 0x76, 0x6d, 0x73, 0x2f, 0x7b, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12,
+	0x60, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x26, 0x2e, 0x73, 0x72, 0x65, 0x61,
+	0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x56, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x22, 0x13, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x0d, 0x3a, 0x02, 0x76, 0x6d, 0x22, 0x07, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d,
+	0x73, 0x12, 0x85, 0x01, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x26, 0x2e, 0x73,
+	0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32,
+	0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x22,
+	0x38, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x32, 0x3a, 0x02, 0x76, 0x6d, 0x5a, 0x18, 0x3a, 0x02, 0x76,
+	0x6d, 0x32, 0x12, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d, 0x73, 0x2f, 0x7b, 0x68, 0x6f, 0x73, 0x74,
+	0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x1a, 0x12, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d, 0x73, 0x2f, 0x7b,
+	0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12, 0x64, 0x0a, 0x06, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x12, 0x26, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x2a, 0x12, 0x2f, 0x76, 0x32,
+	0x2f, 0x76, 0x6d, 0x73, 0x2f, 0x7b, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12,
+	0x8a, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12,
+	0x2c, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e,
+	0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x18, 0x3a, 0x01, 0x2a, 0x22, 0x13, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d, 0x73,
+	0x3a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x82, 0x01, 0x0a,
+	0x11, 0x47, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x12, 0x23, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x47,
+	0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x22, 0x2d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x27, 0x12, 0x25, 0x2f, 0x76, 0x32, 0x2f,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2f, 0x7b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x7d, 0x2f, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x12, 0xa3, 0x01, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x23, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x76, 0x32, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73,
+	0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x4e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x48, 0x3a,
+	0x0f, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x1a, 0x35, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x2f, 0x7b,
+	0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x7d, 0x2f, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x80, 0x01, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x2e, 0x73,
+	0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x65, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x23, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x32, 0x2e, 0x41, 0x6c, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d, 0x3a, 0x01, 0x2a,
+	0x22, 0x18, 0x2f, 0x76, 0x32, 0x2f, 0x76, 0x6d, 0x73, 0x3a, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x6f,
+	0x2f, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x76, 0x32, 0x3b, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protobuf_v2_vm_proto_rawDescOnce sync.Once
+	file_protobuf_v2_vm_proto_rawDescData = file_protobuf_v2_vm_proto_rawDesc
+)
+
+func file_protobuf_v2_vm_proto_rawDescGZIP() []byte {
+	file_protobuf_v2_vm_proto_rawDescOnce.Do(func() {
+		file_protobuf_v2_vm_proto_rawDescData = protoimpl.X.CompressGZIP(file_protobuf_v2_vm_proto_rawDescData)
+	})
+	return file_protobuf_v2_vm_proto_rawDescData
+}
+
+var file_protobuf_v2_vm_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_protobuf_v2_vm_proto_goTypes = []interface{}{
+	(*Sizing)(nil),                             // 0: sreapi.v2.Sizing
+	(*Virtualmachine)(nil),                     // 1: sreapi.v2.Virtualmachine
+	(*ListVirtualmachinesRequest)(nil),         // 2: sreapi.v2.ListVirtualmachinesRequest
+	(*ListVirtualmachinesResponse)(nil),        // 3: sreapi.v2.ListVirtualmachinesResponse
+	(*GetVirtualmachineRequest)(nil),           // 4: sreapi.v2.GetVirtualmachineRequest
+	(*CreateVirtualmachineRequest)(nil),        // 5: sreapi.v2.CreateVirtualmachineRequest
+	(*UpdateVirtualmachineRequest)(nil),        // 6: sreapi.v2.UpdateVirtualmachineRequest
+	(*DeleteVirtualmachineRequest)(nil),        // 7: sreapi.v2.DeleteVirtualmachineRequest
+	(*BatchCreateVirtualmachinesRequest)(nil),  // 8: sreapi.v2.BatchCreateVirtualmachinesRequest
+	(*BatchCreateVirtualmachinesResponse)(nil), // 9: sreapi.v2.BatchCreateVirtualmachinesResponse
+	(*NamingTemplate)(nil),                     // 10: sreapi.v2.NamingTemplate
+	(*GetNamingTemplateRequest)(nil),           // 11: sreapi.v2.GetNamingTemplateRequest
+	(*SetNamingTemplateRequest)(nil),           // 12: sreapi.v2.SetNamingTemplateRequest
+	(*AllocateHostnameRequest)(nil),            // 13: sreapi.v2.AllocateHostnameRequest
+	(*AllocateHostnameResponse)(nil),           // 14: sreapi.v2.AllocateHostnameResponse
+	nil,                                        // 15: sreapi.v2.Virtualmachine.LabelsEntry
+	(*fieldmaskpb.FieldMask)(nil),              // 16: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                      // 17: google.protobuf.Empty
+}
+var file_protobuf_v2_vm_proto_depIdxs = []int32{
+	15, // 0: sreapi.v2.Virtualmachine.labels:type_name -> sreapi.v2.Virtualmachine.LabelsEntry
+	0,  // 1: sreapi.v2.Virtualmachine.sizing:type_name -> sreapi.v2.Sizing
+	1,  // 2: sreapi.v2.ListVirtualmachinesResponse.vms:type_name -> sreapi.v2.Virtualmachine
+	1,  // 3: sreapi.v2.CreateVirtualmachineRequest.vm:type_name -> sreapi.v2.Virtualmachine
+	1,  // 4: sreapi.v2.UpdateVirtualmachineRequest.vm:type_name -> sreapi.v2.Virtualmachine
+	16, // 5: sreapi.v2.UpdateVirtualmachineRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 6: sreapi.v2.BatchCreateVirtualmachinesRequest.vms:type_name -> sreapi.v2.Virtualmachine
+	1,  // 7: sreapi.v2.BatchCreateVirtualmachinesResponse.vms:type_name -> sreapi.v2.Virtualmachine
+	10, // 8: sreapi.v2.SetNamingTemplateRequest.naming_template:type_name -> sreapi.v2.NamingTemplate
+	2,  // 9: sreapi.v2.Virtualmachines.List:input_type -> sreapi.v2.ListVirtualmachinesRequest
+	4,  // 10: sreapi.v2.Virtualmachines.Get:input_type -> sreapi.v2.GetVirtualmachineRequest
+	5,  // 11: sreapi.v2.Virtualmachines.Create:input_type -> sreapi.v2.CreateVirtualmachineRequest
+	6,  // 12: sreapi.v2.Virtualmachines.Update:input_type -> sreapi.v2.UpdateVirtualmachineRequest
+	7,  // 13: sreapi.v2.Virtualmachines.Delete:input_type -> sreapi.v2.DeleteVirtualmachineRequest
+	8,  // 14: sreapi.v2.Virtualmachines.BatchCreate:input_type -> sreapi.v2.BatchCreateVirtualmachinesRequest
+	11, // 15: sreapi.v2.Virtualmachines.GetNamingTemplate:input_type -> sreapi.v2.GetNamingTemplateRequest
+	12, // 16: sreapi.v2.Virtualmachines.SetNamingTemplate:input_type -> sreapi.v2.SetNamingTemplateRequest
+	13, // 17: sreapi.v2.Virtualmachines.AllocateHostname:input_type -> sreapi.v2.AllocateHostnameRequest
+	3,  // 18: sreapi.v2.Virtualmachines.List:output_type -> sreapi.v2.ListVirtualmachinesResponse
+	1,  // 19: sreapi.v2.Virtualmachines.Get:output_type -> sreapi.v2.Virtualmachine
+	1,  // 20: sreapi.v2.Virtualmachines.Create:output_type -> sreapi.v2.Virtualmachine
+	1,  // 21: sreapi.v2.Virtualmachines.Update:output_type -> sreapi.v2.Virtualmachine
+	17, // 22: sreapi.v2.Virtualmachines.Delete:output_type -> google.protobuf.Empty
+	9,  // 23: sreapi.v2.Virtualmachines.BatchCreate:output_type -> sreapi.v2.BatchCreateVirtualmachinesResponse
+	10, // 24: sreapi.v2.Virtualmachines.GetNamingTemplate:output_type -> sreapi.v2.NamingTemplate
+	10, // 25: sreapi.v2.Virtualmachines.SetNamingTemplate:output_type -> sreapi.v2.NamingTemplate
+	14, // 26: sreapi.v2.Virtualmachines.AllocateHostname:output_type -> sreapi.v2.AllocateHostnameResponse
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_protobuf_v2_vm_proto_init() }
+func file_protobuf_v2_vm_proto_init() {
+	if File_protobuf_v2_vm_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_protobuf_v2_vm_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sizing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Virtualmachine); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListVirtualmachinesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListVirtualmachinesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVirtualmachineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateVirtualmachineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateVirtualmachineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteVirtualmachineRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateVirtualmachinesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchCreateVirtualmachinesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NamingTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNamingTemplateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNamingTemplateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateHostnameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_v2_vm_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllocateHostnameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protobuf_v2_vm_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_protobuf_v2_vm_proto_goTypes,
+		DependencyIndexes: file_protobuf_v2_vm_proto_depIdxs,
+		MessageInfos:      file_protobuf_v2_vm_proto_msgTypes,
+	}.Build()
+	File_protobuf_v2_vm_proto = out.File
+	file_protobuf_v2_vm_proto_rawDesc = nil
+	file_protobuf_v2_vm_proto_goTypes = nil
+	file_protobuf_v2_vm_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// VirtualmachinesClient is the client API for Virtualmachines service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type VirtualmachinesClient interface {
+	List(ctx context.Context, in *ListVirtualmachinesRequest, opts ...grpc.CallOption) (*ListVirtualmachinesResponse, error)
+	Get(ctx context.Context, in *GetVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error)
+	Create(ctx context.Context, in *CreateVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error)
+	Update(ctx context.Context, in *UpdateVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error)
+	Delete(ctx context.Context, in *DeleteVirtualmachineRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// BatchCreate creates all the vms or none of them
+	BatchCreate(ctx context.Context, in *BatchCreateVirtualmachinesRequest, opts ...grpc.CallOption) (*BatchCreateVirtualmachinesResponse, error)
+	// GetNamingTemplate returns the project's template, or the default
+	// {project}-{role}-{nn} when it has none
+	GetNamingTemplate(ctx context.Context, in *GetNamingTemplateRequest, opts ...grpc.CallOption) (*NamingTemplate, error)
+	SetNamingTemplate(ctx context.Context, in *SetNamingTemplateRequest, opts ...grpc.CallOption) (*NamingTemplate, error)
+	// AllocateHostname reserves the next free hostname from the project's
+	// naming template
+	AllocateHostname(ctx context.Context, in *AllocateHostnameRequest, opts ...grpc.CallOption) (*AllocateHostnameResponse, error)
+}
+
+type virtualmachinesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVirtualmachinesClient(cc grpc.ClientConnInterface) VirtualmachinesClient {
+	return &virtualmachinesClient{cc}
+}
+
+func (c *virtualmachinesClient) List(ctx context.Context, in *ListVirtualmachinesRequest, opts ...grpc.CallOption) (*ListVirtualmachinesResponse, error) {
+	out := new(ListVirtualmachinesResponse)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) Get(ctx context.Context, in *GetVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error) {
+	out := new(Virtualmachine)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) Create(ctx context.Context, in *CreateVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error) {
+	out := new(Virtualmachine)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) Update(ctx context.Context, in *UpdateVirtualmachineRequest, opts ...grpc.CallOption) (*Virtualmachine, error) {
+	out := new(Virtualmachine)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) Delete(ctx context.Context, in *DeleteVirtualmachineRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) BatchCreate(ctx context.Context, in *BatchCreateVirtualmachinesRequest, opts ...grpc.CallOption) (*BatchCreateVirtualmachinesResponse, error) {
+	out := new(BatchCreateVirtualmachinesResponse)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) GetNamingTemplate(ctx context.Context, in *GetNamingTemplateRequest, opts ...grpc.CallOption) (*NamingTemplate, error) {
+	out := new(NamingTemplate)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/GetNamingTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) SetNamingTemplate(ctx context.Context, in *SetNamingTemplateRequest, opts ...grpc.CallOption) (*NamingTemplate, error) {
+	out := new(NamingTemplate)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/SetNamingTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *virtualmachinesClient) AllocateHostname(ctx context.Context, in *AllocateHostnameRequest, opts ...grpc.CallOption) (*AllocateHostnameResponse, error) {
+	out := new(AllocateHostnameResponse)
+	err := c.cc.Invoke(ctx, "/sreapi.v2.Virtualmachines/AllocateHostname", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VirtualmachinesServer is the server API for Virtualmachines service.
+type VirtualmachinesServer interface {
+	List(context.Context, *ListVirtualmachinesRequest) (*ListVirtualmachinesResponse, error)
+	Get(context.Context, *GetVirtualmachineRequest) (*Virtualmachine, error)
+	Create(context.Context, *CreateVirtualmachineRequest) (*Virtualmachine, error)
+	Update(context.Context, *UpdateVirtualmachineRequest) (*Virtualmachine, error)
+	Delete(context.Context, *DeleteVirtualmachineRequest) (*emptypb.Empty, error)
+	// BatchCreate creates all the vms or none of them
+	BatchCreate(context.Context, *BatchCreateVirtualmachinesRequest) (*BatchCreateVirtualmachinesResponse, error)
+	// GetNamingTemplate returns the project's template, or the default
+	// {project}-{role}-{nn} when it has none
+	GetNamingTemplate(context.Context, *GetNamingTemplateRequest) (*NamingTemplate, error)
+	SetNamingTemplate(context.Context, *SetNamingTemplateRequest) (*NamingTemplate, error)
+	// AllocateHostname reserves the next free hostname from the project's
+	// naming template
+	AllocateHostname(context.Context, *AllocateHostnameRequest) (*AllocateHostnameResponse, error)
+}
+
+// UnimplementedVirtualmachinesServer can be embedded to have forward compatible implementations.
+type UnimplementedVirtualmachinesServer struct {
+}
+
+func (*UnimplementedVirtualmachinesServer) List(context.Context, *ListVirtualmachinesRequest) (*ListVirtualmachinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Get(context.Context, *GetVirtualmachineRequest) (*Virtualmachine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Create(context.Context, *CreateVirtualmachineRequest) (*Virtualmachine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Update(context.Context, *UpdateVirtualmachineRequest) (*Virtualmachine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Delete(context.Context, *DeleteVirtualmachineRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) BatchCreate(context.Context, *BatchCreateVirtualmachinesRequest) (*BatchCreateVirtualmachinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) GetNamingTemplate(context.Context, *GetNamingTemplateRequest) (*NamingTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNamingTemplate not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) SetNamingTemplate(context.Context, *SetNamingTemplateRequest) (*NamingTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamingTemplate not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) AllocateHostname(context.Context, *AllocateHostnameRequest) (*AllocateHostnameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateHostname not implemented")
+}
+
+func RegisterVirtualmachinesServer(s *grpc.Server, srv VirtualmachinesServer) {
+	s.RegisterService(&_Virtualmachines_serviceDesc, srv)
+}
+
+func _Virtualmachines_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVirtualmachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).List(ctx, req.(*ListVirtualmachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVirtualmachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).Get(ctx, req.(*GetVirtualmachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVirtualmachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).Create(ctx, req.(*CreateVirtualmachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVirtualmachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).Update(ctx, req.(*UpdateVirtualmachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVirtualmachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).Delete(ctx, req.(*DeleteVirtualmachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateVirtualmachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).BatchCreate(ctx, req.(*BatchCreateVirtualmachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_GetNamingTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamingTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).GetNamingTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/GetNamingTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).GetNamingTemplate(ctx, req.(*GetNamingTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_SetNamingTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamingTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).SetNamingTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/SetNamingTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).SetNamingTemplate(ctx, req.(*SetNamingTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Virtualmachines_AllocateHostname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateHostnameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VirtualmachinesServer).AllocateHostname(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/sreapi.v2.Virtualmachines/AllocateHostname",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VirtualmachinesServer).AllocateHostname(ctx, req.(*AllocateHostnameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Virtualmachines_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "sreapi.v2.Virtualmachines",
+	HandlerType: (*VirtualmachinesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Virtualmachines_List_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Virtualmachines_Get_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _Virtualmachines_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Virtualmachines_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Virtualmachines_Delete_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _Virtualmachines_BatchCreate_Handler,
+		},
+		{
+			MethodName: "GetNamingTemplate",
+			Handler:    _Virtualmachines_GetNamingTemplate_Handler,
+		},
+		{
+			MethodName: "SetNamingTemplate",
+			Handler:    _Virtualmachines_SetNamingTemplate_Handler,
+		},
+		{
+			MethodName: "AllocateHostname",
+			Handler:    _Virtualmachines_AllocateHostname_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "protobuf/v2/vm.proto",
+}
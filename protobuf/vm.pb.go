@@ -1,667 +1,1010 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
 // source: protobuf/vm.proto
 
 package sreapi
 
 import (
 	context "context"
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
-	math "math"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 type Virtualmachine struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Hostname             string   `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Project              string   `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
-	Role                 string   `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Virtualmachine) Reset()         { *m = Virtualmachine{} }
-func (m *Virtualmachine) String() string { return proto.CompactTextString(m) }
-func (*Virtualmachine) ProtoMessage()    {}
-func (*Virtualmachine) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{0}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	XApi     string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Hostname string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Project  string `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
+	Role     string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *Virtualmachine) Reset() {
+	*x = Virtualmachine{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *Virtualmachine) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Virtualmachine.Unmarshal(m, b)
-}
-func (m *Virtualmachine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Virtualmachine.Marshal(b, m, deterministic)
-}
-func (m *Virtualmachine) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Virtualmachine.Merge(m, src)
-}
-func (m *Virtualmachine) XXX_Size() int {
-	return xxx_messageInfo_Virtualmachine.Size(m)
+func (x *Virtualmachine) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Virtualmachine) XXX_DiscardUnknown() {
-	xxx_messageInfo_Virtualmachine.DiscardUnknown(m)
+
+func (*Virtualmachine) ProtoMessage() {}
+
+func (x *Virtualmachine) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_Virtualmachine proto.InternalMessageInfo
+// Deprecated: Use Virtualmachine.ProtoReflect.Descriptor instead.
+func (*Virtualmachine) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{0}
+}
 
-func (m *Virtualmachine) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *Virtualmachine) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *Virtualmachine) GetHostname() string {
-	if m != nil {
-		return m.Hostname
+func (x *Virtualmachine) GetHostname() string {
+	if x != nil {
+		return x.Hostname
 	}
 	return ""
 }
 
-func (m *Virtualmachine) GetProject() string {
-	if m != nil {
-		return m.Project
+func (x *Virtualmachine) GetProject() string {
+	if x != nil {
+		return x.Project
 	}
 	return ""
 }
 
-func (m *Virtualmachine) GetRole() string {
-	if m != nil {
-		return m.Role
+func (x *Virtualmachine) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
 type ListRequest struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Project              string   `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
-	Role                 string   `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ListRequest) Reset()         { *m = ListRequest{} }
-func (m *ListRequest) String() string { return proto.CompactTextString(m) }
-func (*ListRequest) ProtoMessage()    {}
-func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{1}
+	XApi    string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Project string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	Role    string `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
 }
 
-func (m *ListRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
-}
-func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
-}
-func (m *ListRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListRequest.Merge(m, src)
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ListRequest) XXX_Size() int {
-	return xxx_messageInfo_ListRequest.Size(m)
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ListRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *ListRequest) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *ListRequest) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *ListRequest) GetProject() string {
-	if m != nil {
-		return m.Project
+func (x *ListRequest) GetProject() string {
+	if x != nil {
+		return x.Project
 	}
 	return ""
 }
 
-func (m *ListRequest) GetRole() string {
-	if m != nil {
-		return m.Role
+func (x *ListRequest) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
 type ListResponse struct {
-	XApi                 string            `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Vms                  []*Virtualmachine `protobuf:"bytes,2,rep,name=vms,proto3" json:"vms,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *ListResponse) Reset()         { *m = ListResponse{} }
-func (m *ListResponse) String() string { return proto.CompactTextString(m) }
-func (*ListResponse) ProtoMessage()    {}
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{2}
+	XApi string            `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Vms  []*Virtualmachine `protobuf:"bytes,2,rep,name=vms,proto3" json:"vms,omitempty"`
 }
 
-func (m *ListResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
-}
-func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
-}
-func (m *ListResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListResponse.Merge(m, src)
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *ListResponse) XXX_Size() int {
-	return xxx_messageInfo_ListResponse.Size(m)
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *ListResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{2}
+}
 
-func (m *ListResponse) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *ListResponse) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *ListResponse) GetVms() []*Virtualmachine {
-	if m != nil {
-		return m.Vms
+func (x *ListResponse) GetVms() []*Virtualmachine {
+	if x != nil {
+		return x.Vms
 	}
 	return nil
 }
 
 type GetRequest struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Hostname             string   `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *GetRequest) Reset()         { *m = GetRequest{} }
-func (m *GetRequest) String() string { return proto.CompactTextString(m) }
-func (*GetRequest) ProtoMessage()    {}
-func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{3}
+	XApi     string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Hostname string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
 }
 
-func (m *GetRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
-}
-func (m *GetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetRequest.Marshal(b, m, deterministic)
-}
-func (m *GetRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetRequest.Merge(m, src)
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *GetRequest) XXX_Size() int {
-	return xxx_messageInfo_GetRequest.Size(m)
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *GetRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetRequest.DiscardUnknown(m)
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_GetRequest proto.InternalMessageInfo
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{3}
+}
 
-func (m *GetRequest) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *GetRequest) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *GetRequest) GetHostname() string {
-	if m != nil {
-		return m.Hostname
+func (x *GetRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
 	}
 	return ""
 }
 
 type GetResponse struct {
-	XApi                 string          `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Vm                   *Virtualmachine `protobuf:"bytes,2,opt,name=vm,proto3" json:"vm,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *GetResponse) Reset()         { *m = GetResponse{} }
-func (m *GetResponse) String() string { return proto.CompactTextString(m) }
-func (*GetResponse) ProtoMessage()    {}
-func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{4}
+	XApi string          `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Vm   *Virtualmachine `protobuf:"bytes,2,opt,name=vm,proto3" json:"vm,omitempty"`
 }
 
-func (m *GetResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
-}
-func (m *GetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetResponse.Marshal(b, m, deterministic)
-}
-func (m *GetResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetResponse.Merge(m, src)
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *GetResponse) XXX_Size() int {
-	return xxx_messageInfo_GetResponse.Size(m)
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *GetResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetResponse.DiscardUnknown(m)
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_GetResponse proto.InternalMessageInfo
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{4}
+}
 
-func (m *GetResponse) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *GetResponse) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *GetResponse) GetVm() *Virtualmachine {
-	if m != nil {
-		return m.Vm
+func (x *GetResponse) GetVm() *Virtualmachine {
+	if x != nil {
+		return x.Vm
 	}
 	return nil
 }
 
 type CreateRequest struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Hostname             string   `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Project              string   `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
-	Role                 string   `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
-func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
-func (*CreateRequest) ProtoMessage()    {}
-func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{5}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	XApi     string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Hostname string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Project  string `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
+	Role     string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateRequest.Unmarshal(m, b)
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *CreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateRequest.Marshal(b, m, deterministic)
-}
-func (m *CreateRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateRequest.Merge(m, src)
-}
-func (m *CreateRequest) XXX_Size() int {
-	return xxx_messageInfo_CreateRequest.Size(m)
-}
-func (m *CreateRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateRequest.DiscardUnknown(m)
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_CreateRequest proto.InternalMessageInfo
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{5}
+}
 
-func (m *CreateRequest) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *CreateRequest) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *CreateRequest) GetHostname() string {
-	if m != nil {
-		return m.Hostname
+func (x *CreateRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
 	}
 	return ""
 }
 
-func (m *CreateRequest) GetProject() string {
-	if m != nil {
-		return m.Project
+func (x *CreateRequest) GetProject() string {
+	if x != nil {
+		return x.Project
 	}
 	return ""
 }
 
-func (m *CreateRequest) GetRole() string {
-	if m != nil {
-		return m.Role
+func (x *CreateRequest) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
 type CreateResponse struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
-func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
-func (*CreateResponse) ProtoMessage()    {}
-func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{6}
+	XApi    string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
 }
 
-func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateResponse.Unmarshal(m, b)
-}
-func (m *CreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateResponse.Marshal(b, m, deterministic)
-}
-func (m *CreateResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateResponse.Merge(m, src)
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *CreateResponse) XXX_Size() int {
-	return xxx_messageInfo_CreateResponse.Size(m)
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *CreateResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateResponse.DiscardUnknown(m)
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_CreateResponse proto.InternalMessageInfo
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{6}
+}
 
-func (m *CreateResponse) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *CreateResponse) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *CreateResponse) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *CreateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
 	}
 	return false
 }
 
 type UpdateRequest struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Hostname             string   `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Project              string   `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
-	Role                 string   `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
-	Oldhostname          string   `protobuf:"bytes,5,opt,name=oldhostname,proto3" json:"oldhostname,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *UpdateRequest) Reset()         { *m = UpdateRequest{} }
-func (m *UpdateRequest) String() string { return proto.CompactTextString(m) }
-func (*UpdateRequest) ProtoMessage()    {}
-func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{7}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	XApi        string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Hostname    string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Project     string `protobuf:"bytes,3,opt,name=project,proto3" json:"project,omitempty"`
+	Role        string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	Oldhostname string `protobuf:"bytes,5,opt,name=oldhostname,proto3" json:"oldhostname,omitempty"`
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *UpdateRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateRequest.Unmarshal(m, b)
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *UpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateRequest.Marshal(b, m, deterministic)
-}
-func (m *UpdateRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateRequest.Merge(m, src)
-}
-func (m *UpdateRequest) XXX_Size() int {
-	return xxx_messageInfo_UpdateRequest.Size(m)
-}
-func (m *UpdateRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateRequest.DiscardUnknown(m)
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_UpdateRequest proto.InternalMessageInfo
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{7}
+}
 
-func (m *UpdateRequest) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *UpdateRequest) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *UpdateRequest) GetHostname() string {
-	if m != nil {
-		return m.Hostname
+func (x *UpdateRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
 	}
 	return ""
 }
 
-func (m *UpdateRequest) GetProject() string {
-	if m != nil {
-		return m.Project
+func (x *UpdateRequest) GetProject() string {
+	if x != nil {
+		return x.Project
 	}
 	return ""
 }
 
-func (m *UpdateRequest) GetRole() string {
-	if m != nil {
-		return m.Role
+func (x *UpdateRequest) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
-func (m *UpdateRequest) GetOldhostname() string {
-	if m != nil {
-		return m.Oldhostname
+func (x *UpdateRequest) GetOldhostname() string {
+	if x != nil {
+		return x.Oldhostname
 	}
 	return ""
 }
 
 type UpdateResponse struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
-func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
-func (*UpdateResponse) ProtoMessage()    {}
-func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{8}
+	XApi    string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
 }
 
-func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateResponse.Unmarshal(m, b)
-}
-func (m *UpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateResponse.Marshal(b, m, deterministic)
-}
-func (m *UpdateResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateResponse.Merge(m, src)
+func (x *UpdateResponse) Reset() {
+	*x = UpdateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *UpdateResponse) XXX_Size() int {
-	return xxx_messageInfo_UpdateResponse.Size(m)
+
+func (x *UpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *UpdateResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateResponse.DiscardUnknown(m)
+
+func (*UpdateResponse) ProtoMessage() {}
+
+func (x *UpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_UpdateResponse proto.InternalMessageInfo
+// Deprecated: Use UpdateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{8}
+}
 
-func (m *UpdateResponse) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *UpdateResponse) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *UpdateResponse) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *UpdateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
 	}
 	return false
 }
 
 type DeleteRequest struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Hostname             string   `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
-func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
-func (*DeleteRequest) ProtoMessage()    {}
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{9}
+	XApi     string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Hostname string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
 }
 
-func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
-}
-func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
-}
-func (m *DeleteRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteRequest.Merge(m, src)
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *DeleteRequest) XXX_Size() int {
-	return xxx_messageInfo_DeleteRequest.Size(m)
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *DeleteRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{9}
+}
 
-func (m *DeleteRequest) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *DeleteRequest) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *DeleteRequest) GetHostname() string {
-	if m != nil {
-		return m.Hostname
+func (x *DeleteRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
 	}
 	return ""
 }
 
 type DeleteResponse struct {
-	XApi                 string   `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
-func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
-func (*DeleteResponse) ProtoMessage()    {}
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51142f146fd9438b, []int{10}
+	XApi    string `protobuf:"bytes,1,opt,name=_api,json=Api,proto3" json:"_api,omitempty"`
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
 }
 
-func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
-}
-func (m *DeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteResponse.Marshal(b, m, deterministic)
-}
-func (m *DeleteResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteResponse.Merge(m, src)
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protobuf_vm_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *DeleteResponse) XXX_Size() int {
-	return xxx_messageInfo_DeleteResponse.Size(m)
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *DeleteResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteResponse.DiscardUnknown(m)
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_vm_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_vm_proto_rawDescGZIP(), []int{10}
+}
 
-func (m *DeleteResponse) GetXApi() string {
-	if m != nil {
-		return m.XApi
+func (x *DeleteResponse) GetXApi() string {
+	if x != nil {
+		return x.XApi
 	}
 	return ""
 }
 
-func (m *DeleteResponse) GetSuccess() bool {
-	if m != nil {
-		return m.Success
+func (x *DeleteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
 	}
 	return false
 }
 
-func init() {
-	proto.RegisterType((*Virtualmachine)(nil), "sreapi.Virtualmachine")
-	proto.RegisterType((*ListRequest)(nil), "sreapi.ListRequest")
-	proto.RegisterType((*ListResponse)(nil), "sreapi.ListResponse")
-	proto.RegisterType((*GetRequest)(nil), "sreapi.GetRequest")
-	proto.RegisterType((*GetResponse)(nil), "sreapi.GetResponse")
-	proto.RegisterType((*CreateRequest)(nil), "sreapi.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "sreapi.CreateResponse")
-	proto.RegisterType((*UpdateRequest)(nil), "sreapi.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "sreapi.UpdateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "sreapi.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "sreapi.DeleteResponse")
-}
-
-func init() { proto.RegisterFile("protobuf/vm.proto", fileDescriptor_51142f146fd9438b) }
-
-var fileDescriptor_51142f146fd9438b = []byte{
-	// 491 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x55, 0xec, 0x90, 0x96, 0x09, 0x29, 0xea, 0x94, 0xb6, 0xc6, 0x02, 0x11, 0xed, 0x01, 0xa2,
-	0x48, 0x64, 0x45, 0xb8, 0x81, 0x40, 0x42, 0x20, 0x15, 0x89, 0x8f, 0x83, 0x25, 0x7a, 0x45, 0xdb,
-	0x74, 0xdb, 0x1a, 0xd9, 0xde, 0xc5, 0xbb, 0xf1, 0x25, 0xca, 0x85, 0x1f, 0xc0, 0x85, 0x9f, 0xc6,
-	0x95, 0x23, 0x3f, 0x04, 0x79, 0x9d, 0xad, 0xbd, 0x28, 0xa9, 0xc0, 0x87, 0xde, 0x76, 0x66, 0x77,
-	0xde, 0x7b, 0x33, 0xf3, 0x6c, 0xd8, 0x95, 0xb9, 0xd0, 0xe2, 0x64, 0x7e, 0x46, 0x8b, 0x74, 0x62,
-	0xce, 0xd8, 0x53, 0x39, 0x67, 0x32, 0x0e, 0xef, 0x9d, 0x0b, 0x71, 0x9e, 0x70, 0xca, 0x64, 0x4c,
-	0x59, 0x96, 0x09, 0xcd, 0x74, 0x2c, 0x32, 0x55, 0xbd, 0x22, 0x29, 0xec, 0x1c, 0xc7, 0xb9, 0x9e,
-	0xb3, 0x24, 0x65, 0xb3, 0x8b, 0x38, 0xe3, 0xb8, 0x0b, 0xdd, 0xcf, 0x4c, 0xc6, 0x41, 0x67, 0xd8,
-	0x19, 0xdd, 0x8c, 0xfc, 0x57, 0x32, 0xc6, 0x10, 0xb6, 0x2f, 0x84, 0xd2, 0x19, 0x4b, 0x79, 0xe0,
-	0x99, 0xf4, 0x65, 0x8c, 0x01, 0x6c, 0xc9, 0x5c, 0x7c, 0xe1, 0x33, 0x1d, 0xf8, 0xe6, 0xca, 0x86,
-	0x88, 0xd0, 0xcd, 0x45, 0xc2, 0x83, 0xae, 0x49, 0x9b, 0x33, 0xf9, 0x08, 0xfd, 0xf7, 0xb1, 0xd2,
-	0x11, 0xff, 0x3a, 0xe7, 0x4a, 0xaf, 0xe3, 0x6a, 0xe0, 0x79, 0xeb, 0xf1, 0xfc, 0x06, 0xde, 0x3b,
-	0xb8, 0x55, 0xe1, 0x29, 0x29, 0x32, 0xb5, 0x56, 0xfc, 0x08, 0xfc, 0x22, 0x55, 0x81, 0x37, 0xf4,
-	0x47, 0xfd, 0xe9, 0xc1, 0xa4, 0x9a, 0xca, 0xc4, 0x6d, 0x3a, 0x2a, 0x9f, 0x90, 0xe7, 0x00, 0x47,
-	0xfc, 0x2a, 0x6d, 0x57, 0xcc, 0x81, 0xbc, 0x85, 0xbe, 0x29, 0xde, 0x2c, 0xe4, 0x21, 0x78, 0x45,
-	0x6a, 0xea, 0x36, 0xeb, 0xf0, 0x8a, 0x94, 0x24, 0x30, 0x78, 0x9d, 0x73, 0xa6, 0x79, 0x3b, 0x25,
-	0xff, 0xb9, 0x91, 0x17, 0xb0, 0x63, 0xd9, 0x36, 0x4b, 0x0f, 0x60, 0x4b, 0xcd, 0x67, 0x33, 0xae,
-	0x94, 0x61, 0xdb, 0x8e, 0x6c, 0x48, 0xbe, 0x77, 0x60, 0xf0, 0x49, 0x9e, 0x5e, 0x93, 0x5a, 0x1c,
-	0x42, 0x5f, 0x24, 0xa7, 0x97, 0x60, 0x37, 0xcc, 0x55, 0x33, 0x55, 0xf6, 0x63, 0xf5, 0xb4, 0xe9,
-	0xe7, 0x25, 0x0c, 0xde, 0xf0, 0x84, 0xb7, 0x6d, 0xa7, 0xa4, 0xb7, 0xf5, 0x2d, 0xe8, 0xa7, 0xbf,
-	0x7c, 0xb8, 0xed, 0x5a, 0x42, 0x61, 0x04, 0xdd, 0xd2, 0xe3, 0xb8, 0x67, 0x3d, 0xd3, 0xf8, 0x82,
-	0xc2, 0x3b, 0x6e, 0xb2, 0xe2, 0x24, 0x0f, 0xbe, 0xfd, 0xfc, 0xfd, 0xc3, 0xbb, 0x8b, 0x87, 0xb4,
-	0x78, 0x42, 0x8b, 0x94, 0x2e, 0x56, 0x33, 0x5d, 0xd2, 0x45, 0x39, 0xc6, 0x25, 0x7e, 0x00, 0xff,
-	0x88, 0x6b, 0x44, 0x5b, 0x5d, 0xfb, 0x3e, 0xdc, 0x73, 0x72, 0x2b, 0xc0, 0xfb, 0x06, 0xf0, 0x10,
-	0xf7, 0x57, 0x80, 0x63, 0x3a, 0xa6, 0x0b, 0xdb, 0xf4, 0x12, 0xcf, 0xa0, 0x57, 0x99, 0x08, 0xf7,
-	0x6d, 0xb5, 0x63, 0xe1, 0xf0, 0xe0, 0xef, 0xf4, 0x0a, 0xf7, 0xb1, 0xc1, 0x7d, 0x44, 0xc8, 0x06,
-	0xa1, 0x0d, 0x92, 0x67, 0x9d, 0x71, 0xc9, 0x53, 0x2d, 0xb7, 0xe6, 0x71, 0xcc, 0x57, 0xf3, 0xb8,
-	0x1e, 0xb0, 0x3c, 0xd3, 0x7f, 0xe4, 0x39, 0x86, 0x5e, 0xb5, 0xc5, 0x9a, 0xc7, 0x71, 0x45, 0xcd,
-	0xe3, 0x2e, 0xdb, 0xce, 0x69, 0xbc, 0x7e, 0x4e, 0x27, 0x3d, 0xf3, 0xd3, 0x7d, 0xfa, 0x27, 0x00,
-	0x00, 0xff, 0xff, 0x7a, 0x7c, 0x46, 0xfc, 0xaf, 0x05, 0x00, 0x00,
+var File_protobuf_vm_proto protoreflect.FileDescriptor
+
+var file_protobuf_vm_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x76, 0x6d, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x06, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x1a, 0x1c, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6d, 0x0a, 0x0e, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x11, 0x0a, 0x04, 0x5f,
+	0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x1a,
+	0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x4e, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x11, 0x0a, 0x04, 0x5f, 0x61, 0x70, 0x69, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x4b, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x11, 0x0a, 0x04, 0x5f, 0x61, 0x70, 0x69,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x28, 0x0a, 0x03, 0x76,
+	0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x52, 0x03, 0x76, 0x6d, 0x73, 0x22, 0x3b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x11, 0x0a, 0x04, 0x5f, 0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x11, 0x0a, 0x04, 0x5f, 0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x41, 0x70, 0x69, 0x12, 0x26, 0x0a, 0x02, 0x76, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x02, 0x76, 0x6d, 0x22, 0x6c, 0x0a, 0x0d,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x11, 0x0a,
+	0x04, 0x5f, 0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69,
+	0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x3d, 0x0a, 0x0e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x11, 0x0a, 0x04,
+	0x5f, 0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x8e, 0x01, 0x0a, 0x0d, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x11, 0x0a, 0x04, 0x5f,
+	0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x1a,
+	0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x68,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f,
+	0x6c, 0x64, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x0e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x11, 0x0a, 0x04,
+	0x5f, 0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x3e, 0x0a, 0x0d, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x11, 0x0a, 0x04, 0x5f, 0x61,
+	0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x0e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x11, 0x0a, 0x04, 0x5f,
+	0x61, 0x70, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x41, 0x70, 0x69, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x32, 0xdc, 0x03, 0x0a, 0x0f, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x52, 0x0a, 0x04,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x13, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x73, 0x72, 0x65, 0x61,
+	0x70, 0x69, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x6d, 0x2f,
+	0x7b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x7d, 0x2f, 0x7b, 0x72, 0x6f, 0x6c, 0x65, 0x7d,
+	0x12, 0x4d, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x12, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x73, 0x72,
+	0x65, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x6d,
+	0x2f, 0x2a, 0x2f, 0x2a, 0x2f, 0x7b, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12,
+	0x66, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x15, 0x2e, 0x73, 0x72, 0x65, 0x61,
+	0x70, 0x69, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x27,
+	0x3a, 0x01, 0x2a, 0x22, 0x22, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x6d, 0x2f, 0x7b, 0x70, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x7d, 0x2f, 0x7b, 0x72, 0x6f, 0x6c, 0x65, 0x7d, 0x2f, 0x7b, 0x68, 0x6f,
+	0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12, 0x66, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x12, 0x15, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70,
+	0x69, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x2d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x27, 0x3a, 0x01, 0x2a, 0x32, 0x22, 0x2f, 0x76, 0x31,
+	0x2f, 0x76, 0x6d, 0x2f, 0x7b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x7d, 0x2f, 0x7b, 0x72,
+	0x6f, 0x6c, 0x65, 0x7d, 0x2f, 0x7b, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x12,
+	0x56, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x15, 0x2e, 0x73, 0x72, 0x65, 0x61,
+	0x70, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x73, 0x72, 0x65, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17,
+	0x2a, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x6d, 0x2f, 0x2a, 0x2f, 0x2a, 0x2f, 0x7b, 0x68, 0x6f,
+	0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x7d, 0x42, 0x2b, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x6f, 0x2f, 0x73, 0x72,
+	0x65, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x3b, 0x73, 0x72,
+	0x65, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_protobuf_vm_proto_rawDescOnce sync.Once
+	file_protobuf_vm_proto_rawDescData = file_protobuf_vm_proto_rawDesc
+)
+
+func file_protobuf_vm_proto_rawDescGZIP() []byte {
+	file_protobuf_vm_proto_rawDescOnce.Do(func() {
+		file_protobuf_vm_proto_rawDescData = protoimpl.X.CompressGZIP(file_protobuf_vm_proto_rawDescData)
+	})
+	return file_protobuf_vm_proto_rawDescData
+}
+
+var file_protobuf_vm_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_protobuf_vm_proto_goTypes = []interface{}{
+	(*Virtualmachine)(nil), // 0: sreapi.Virtualmachine
+	(*ListRequest)(nil),    // 1: sreapi.ListRequest
+	(*ListResponse)(nil),   // 2: sreapi.ListResponse
+	(*GetRequest)(nil),     // 3: sreapi.GetRequest
+	(*GetResponse)(nil),    // 4: sreapi.GetResponse
+	(*CreateRequest)(nil),  // 5: sreapi.CreateRequest
+	(*CreateResponse)(nil), // 6: sreapi.CreateResponse
+	(*UpdateRequest)(nil),  // 7: sreapi.UpdateRequest
+	(*UpdateResponse)(nil), // 8: sreapi.UpdateResponse
+	(*DeleteRequest)(nil),  // 9: sreapi.DeleteRequest
+	(*DeleteResponse)(nil), // 10: sreapi.DeleteResponse
+}
+var file_protobuf_vm_proto_depIdxs = []int32{
+	0,  // 0: sreapi.ListResponse.vms:type_name -> sreapi.Virtualmachine
+	0,  // 1: sreapi.GetResponse.vm:type_name -> sreapi.Virtualmachine
+	1,  // 2: sreapi.Virtualmachines.List:input_type -> sreapi.ListRequest
+	3,  // 3: sreapi.Virtualmachines.Get:input_type -> sreapi.GetRequest
+	5,  // 4: sreapi.Virtualmachines.Create:input_type -> sreapi.CreateRequest
+	7,  // 5: sreapi.Virtualmachines.Update:input_type -> sreapi.UpdateRequest
+	9,  // 6: sreapi.Virtualmachines.Delete:input_type -> sreapi.DeleteRequest
+	2,  // 7: sreapi.Virtualmachines.List:output_type -> sreapi.ListResponse
+	4,  // 8: sreapi.Virtualmachines.Get:output_type -> sreapi.GetResponse
+	6,  // 9: sreapi.Virtualmachines.Create:output_type -> sreapi.CreateResponse
+	8,  // 10: sreapi.Virtualmachines.Update:output_type -> sreapi.UpdateResponse
+	10, // 11: sreapi.Virtualmachines.Delete:output_type -> sreapi.DeleteResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_protobuf_vm_proto_init() }
+func file_protobuf_vm_proto_init() {
+	if File_protobuf_vm_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_protobuf_vm_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Virtualmachine); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protobuf_vm_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_protobuf_vm_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_protobuf_vm_proto_goTypes,
+		DependencyIndexes: file_protobuf_vm_proto_depIdxs,
+		MessageInfos:      file_protobuf_vm_proto_msgTypes,
+	}.Build()
+	File_protobuf_vm_proto = out.File
+	file_protobuf_vm_proto_rawDesc = nil
+	file_protobuf_vm_proto_goTypes = nil
+	file_protobuf_vm_proto_depIdxs = nil
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConn
+var _ grpc.ClientConnInterface
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+const _ = grpc.SupportPackageIsVersion6
 
 // VirtualmachinesClient is the client API for Virtualmachines service.
 //
@@ -675,10 +1018,10 @@ type VirtualmachinesClient interface {
 }
 
 type virtualmachinesClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewVirtualmachinesClient(cc *grpc.ClientConn) VirtualmachinesClient {
+func NewVirtualmachinesClient(cc grpc.ClientConnInterface) VirtualmachinesClient {
 	return &virtualmachinesClient{cc}
 }
 
@@ -736,6 +1079,26 @@ type VirtualmachinesServer interface {
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 }
 
+// UnimplementedVirtualmachinesServer can be embedded to have forward compatible implementations.
+type UnimplementedVirtualmachinesServer struct {
+}
+
+func (*UnimplementedVirtualmachinesServer) List(context.Context, *ListRequest) (*ListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Update(context.Context, *UpdateRequest) (*UpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedVirtualmachinesServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+
 func RegisterVirtualmachinesServer(s *grpc.Server, srv VirtualmachinesServer) {
 	s.RegisterService(&_Virtualmachines_serviceDesc, srv)
 }
@@ -0,0 +1,289 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/achanno/sreapi/inventory"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dnsTimeout bounds the database lookups for one query
+const dnsTimeout = 2 * time.Second
+
+// maxUDPSize is the largest udp reply sent to EDNS0 clients, small enough not
+// to be fragmented
+const maxUDPSize = 1232
+
+// dnsResponder answers A, AAAA and PTR queries for the vms in zone, and in
+// the reverse zones of the ipam subnets. Every query is looked up in the
+// database, so answers follow the vms as they change. PTR queries for
+// addresses outside every subnet are refused, even when a subnet that is not
+// on an octet or nibble boundary widens its reverse zone over them.
+type dnsResponder struct {
+	store   *store
+	zone    inventory.Zone
+	logger  *slog.Logger
+	queries *prometheus.CounterVec
+
+	udp *dns.Server
+	tcp *dns.Server
+}
+
+func newDNSResponder(reg prometheus.Registerer, st *store, zone string, logger *slog.Logger) (*dnsResponder, error) {
+	queries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "sreapi",
+		Name:      "dns_queries_total",
+		Help:      "DNS queries answered by the built in responder, by query type and response code.",
+	}, []string{"qtype", "rcode"})
+	if err := reg.Register(queries); err != nil {
+		return nil, err
+	}
+	return &dnsResponder{
+		store:   st,
+		zone:    inventory.Zone{Name: zone, TTL: inventory.DefaultTTL, Serial: uint32(time.Now().Unix())},
+		logger:  logger,
+		queries: queries,
+	}, nil
+}
+
+// listen opens the udp and tcp sockets on addr, tcp on the port udp got
+// when addr asks for any port
+func (d *dnsResponder) listen(addr string) error {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		pc.Close()
+		return err
+	}
+	d.udp = &dns.Server{PacketConn: pc, Handler: d}
+	d.tcp = &dns.Server{Listener: l, Handler: d}
+	return nil
+}
+
+// shutdown stops both servers
+func (d *dnsResponder) shutdown(ctx context.Context) {
+	d.udp.ShutdownContext(ctx)
+	d.tcp.ShutdownContext(ctx)
+}
+
+func (d *dnsResponder) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	var name string
+	qtype := "none"
+	if len(r.Question) != 1 {
+		m.Rcode = dns.RcodeFormatError
+	} else {
+		q := r.Question[0]
+		name, qtype = q.Name, dns.TypeToString[q.Qtype]
+		ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+		d.answer(ctx, m, q)
+		cancel()
+	}
+
+	if r.IsEdns0() != nil {
+		m.SetEdns0(maxUDPSize, false)
+	}
+	m.Truncate(replySize(w, r))
+
+	rcode := dns.RcodeToString[m.Rcode]
+	d.queries.WithLabelValues(qtype, rcode).Inc()
+	d.logger.Debug("dns query", "name", name, "qtype", qtype, "rcode", rcode, "answers", len(m.Answer), "truncated", m.Truncated)
+	w.WriteMsg(m)
+}
+
+// replySize is how large the reply to r may be: over udp 512 bytes, or the
+// buffer r advertises with EDNS0 up to maxUDPSize, over tcp a whole message
+func replySize(w dns.ResponseWriter, r *dns.Msg) int {
+	if _, ok := w.RemoteAddr().(*net.TCPAddr); ok {
+		return dns.MaxMsgSize
+	}
+	size := dns.MinMsgSize
+	if opt := r.IsEdns0(); opt != nil && int(opt.UDPSize()) > size {
+		size = min(int(opt.UDPSize()), maxUDPSize)
+	}
+	return size
+}
+
+// answer fills in m for q, from the vms when q is in the zone or the reverse
+// zone of a subnet
+func (d *dnsResponder) answer(ctx context.Context, m *dns.Msg, q dns.Question) {
+	name := strings.ToLower(q.Name)
+	origin := dns.Fqdn(strings.ToLower(d.zone.Name))
+	switch {
+	case name == origin:
+		d.answerApex(m, q, d.zone.SOA())
+		return
+	case dns.IsSubDomain(origin, name):
+		d.answerHost(ctx, m, q, name, origin)
+		return
+	case inventory.IsReverseZone(name):
+		zone, err := d.reverseZone(ctx, name)
+		if err != nil {
+			m.Rcode = dns.RcodeServerFailure
+			return
+		}
+		if zone == nil {
+			break
+		}
+		if name == zone.Hdr.Name {
+			d.answerApex(m, q, zone)
+		} else {
+			d.answerPTR(ctx, m, q, name, zone)
+		}
+		return
+	}
+	m.Authoritative = false
+	m.Rcode = dns.RcodeRefused
+}
+
+// reverseZone is the SOA of the reverse zone of the subnet name is in, the
+// narrowest when subnets nest, nil when no subnet holds it. The reverse name
+// of an address is only in the zones of the subnets holding the address.
+func (d *dnsResponder) reverseZone(ctx context.Context, name string) (*dns.SOA, error) {
+	subnets, err := d.store.listSubnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addr, isAddr := reverseAddr(name)
+	var origin string
+	for _, sn := range subnets {
+		p, err := netip.ParsePrefix(sn.Cidr)
+		if err != nil || (isAddr && !p.Contains(addr)) {
+			continue
+		}
+		if z := inventory.ReverseZone(p); dns.IsSubDomain(z, name) && len(z) > len(origin) {
+			origin = z
+		}
+	}
+	if origin == "" {
+		return nil, nil
+	}
+	z := inventory.Zone{Name: origin, Domain: d.zone.Name, Serial: d.zone.Serial, TTL: d.zone.TTL}
+	return z.SOA(), nil
+}
+
+func (d *dnsResponder) answerApex(m *dns.Msg, q dns.Question, soa *dns.SOA) {
+	switch q.Qtype {
+	case dns.TypeSOA:
+		m.Answer = append(m.Answer, soa)
+	case dns.TypeNS:
+		m.Answer = append(m.Answer, &dns.NS{Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: soa.Hdr.Ttl}, Ns: soa.Ns})
+	default:
+		m.Ns = append(m.Ns, soa)
+	}
+}
+
+// answerHost answers for the vm called name, or name without the zone
+func (d *dnsResponder) answerHost(ctx context.Context, m *dns.Msg, q dns.Question, name, origin string) {
+	var vm *pbv2.Virtualmachine
+	for _, hostname := range []string{strings.TrimSuffix(name, "."+origin), strings.TrimSuffix(name, ".")} {
+		found, err := d.store.getVM(ctx, hostname, false)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			m.Rcode = dns.RcodeServerFailure
+			return
+		}
+		vm = found
+		break
+	}
+	if vm == nil {
+		m.Rcode = dns.RcodeNameError
+		m.Ns = append(m.Ns, d.zone.SOA())
+		return
+	}
+
+	for _, a := range vm.Addresses {
+		addr, err := netip.ParseAddr(a)
+		if err != nil {
+			continue
+		}
+		if (q.Qtype == dns.TypeA && addr.Is4()) || (q.Qtype == dns.TypeAAAA && addr.Is6()) || q.Qtype == dns.TypeANY {
+			m.Answer = append(m.Answer, inventory.AddressRecord(q.Name, addr, d.zone.TTL))
+		}
+	}
+	if len(m.Answer) == 0 {
+		m.Ns = append(m.Ns, d.zone.SOA())
+	}
+}
+
+// answerPTR answers for the vm with the address name is the reverse of,
+// name is in the reverse zone with the SOA soa
+func (d *dnsResponder) answerPTR(ctx context.Context, m *dns.Msg, q dns.Question, name string, soa *dns.SOA) {
+	addr, ok := reverseAddr(name)
+	if !ok {
+		m.Rcode = dns.RcodeNameError
+		m.Ns = append(m.Ns, soa)
+		return
+	}
+	vm, err := d.store.vmByAddress(ctx, addr.String())
+	if status.Code(err) == codes.NotFound {
+		m.Rcode = dns.RcodeNameError
+		m.Ns = append(m.Ns, soa)
+		return
+	}
+	if err != nil {
+		m.Rcode = dns.RcodeServerFailure
+		return
+	}
+	if q.Qtype == dns.TypePTR || q.Qtype == dns.TypeANY {
+		m.Answer = append(m.Answer, &dns.PTR{
+			Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: d.zone.TTL},
+			Ptr: inventory.FQDN(vm.Hostname, d.zone.Name),
+		})
+	}
+	if len(m.Answer) == 0 {
+		m.Ns = append(m.Ns, soa)
+	}
+}
+
+// reverseAddr is the address an in-addr.arpa or ip6.arpa name is for
+func reverseAddr(name string) (netip.Addr, bool) {
+	name = strings.TrimSuffix(name, ".")
+	if v4, ok := strings.CutSuffix(name, ".in-addr.arpa"); ok {
+		octets := strings.Split(v4, ".")
+		if len(octets) != 4 {
+			return netip.Addr{}, false
+		}
+		for i, j := 0, len(octets)-1; i < j; i, j = i+1, j-1 {
+			octets[i], octets[j] = octets[j], octets[i]
+		}
+		addr, err := netip.ParseAddr(strings.Join(octets, "."))
+		return addr, err == nil
+	}
+	if v6, ok := strings.CutSuffix(name, ".ip6.arpa"); ok {
+		nibbles := strings.Split(v6, ".")
+		if len(nibbles) != 32 {
+			return netip.Addr{}, false
+		}
+		var b strings.Builder
+		for i := len(nibbles) - 1; i >= 0; i-- {
+			if len(nibbles[i]) != 1 {
+				return netip.Addr{}, false
+			}
+			b.WriteString(nibbles[i])
+			if i%4 == 0 && i > 0 {
+				b.WriteByte(':')
+			}
+		}
+		addr, err := netip.ParseAddr(b.String())
+		return addr, err == nil
+	}
+	return netip.Addr{}, false
+}
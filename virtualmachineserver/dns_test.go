@@ -0,0 +1,149 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/achanno/sreapi/inventory"
+	"github.com/miekg/dns"
+)
+
+func TestReverseAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"4.3.2.10.in-addr.arpa.", "10.2.3.4"},
+		{"3.2.10.in-addr.arpa.", ""},
+		{"5.4.3.2.10.in-addr.arpa.", ""},
+		{"256.3.2.10.in-addr.arpa.", ""},
+		{"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", "2001:db8::1"},
+		{"0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"10.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"g.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.", ""},
+		{"web01.example.internal.", ""},
+	}
+	for _, tt := range tests {
+		addr, ok := reverseAddr(tt.name)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("reverseAddr(%q) = %s, want none", tt.name, addr)
+			}
+			continue
+		}
+		if !ok || addr.String() != tt.want {
+			t.Errorf("reverseAddr(%q) = %s, %v, want %s", tt.name, addr, ok, tt.want)
+		}
+	}
+
+	// Names are read back as the address miekg/dns made them from
+	for _, a := range []string{"192.0.2.1", "0.0.0.0", "2001:db8::ff00:42:8329", "::1"} {
+		name, err := dns.ReverseAddr(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr, ok := reverseAddr(name); !ok || addr.String() != a {
+			t.Errorf("reverseAddr(%q) = %s, %v, want %s", name, addr, ok, a)
+		}
+	}
+}
+
+// testWriter is a dns.ResponseWriter for a client at remote
+type testWriter struct {
+	dns.ResponseWriter
+	remote net.Addr
+}
+
+func (w testWriter) RemoteAddr() net.Addr { return w.remote }
+
+func TestReplySize(t *testing.T) {
+	udp := testWriter{remote: &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 5353}}
+	tcp := testWriter{remote: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 5353}}
+	query := func(bufsize uint16) *dns.Msg {
+		m := new(dns.Msg)
+		m.SetQuestion("web01.example.internal.", dns.TypeA)
+		if bufsize > 0 {
+			m.SetEdns0(bufsize, false)
+		}
+		return m
+	}
+	tests := []struct {
+		w       dns.ResponseWriter
+		bufsize uint16
+		want    int
+	}{
+		{udp, 0, dns.MinMsgSize},
+		{udp, 256, dns.MinMsgSize},
+		{udp, 1024, 1024},
+		{udp, 4096, maxUDPSize},
+		{tcp, 0, dns.MaxMsgSize},
+		{tcp, 1024, dns.MaxMsgSize},
+	}
+	for _, tt := range tests {
+		if got := replySize(tt.w, query(tt.bufsize)); got != tt.want {
+			t.Errorf("replySize(%s, %d) = %d, want %d", tt.w.RemoteAddr().Network(), tt.bufsize, got, tt.want)
+		}
+	}
+
+	// A vm with many addresses does not fit 512 bytes
+	r := query(0)
+	m := new(dns.Msg)
+	m.SetReply(r)
+	for i := 0; i < 64; i++ {
+		m.Answer = append(m.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 300},
+			A:   net.IPv4(10, 0, 0, byte(i)),
+		})
+	}
+	m.Truncate(replySize(udp, r))
+	if !m.Truncated || m.Len() > dns.MinMsgSize {
+		t.Errorf("udp reply is %d bytes, truncated %v, want at most %d and truncated", m.Len(), m.Truncated, dns.MinMsgSize)
+	}
+}
+
+func TestReverseZoneOnlyHoldsSubnets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	d := &dnsResponder{store: &store{db: db}, zone: inventory.Zone{Name: "example.internal", TTL: 300}}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"1.0.2.10.in-addr.arpa.", "10.in-addr.arpa."},
+		{"5.3.1.10.in-addr.arpa.", "1.10.in-addr.arpa."},
+		// in 10.in-addr.arpa, the zone of 10.0.0.0/12, but not in the subnet
+		{"1.0.200.10.in-addr.arpa.", ""},
+		{"10.in-addr.arpa.", "10.in-addr.arpa."},
+		{"1.0.0.192.in-addr.arpa.", ""},
+	}
+	for _, tt := range tests {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT Cidr, Description, Gateway FROM subnets")).
+			WillReturnRows(sqlmock.NewRows([]string{"Cidr", "Description", "Gateway"}).
+				AddRow("10.0.0.0/12", "", "").
+				AddRow("10.1.0.0/16", "", ""))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT Cidr, Start, End FROM subnet_pools")).
+			WillReturnRows(sqlmock.NewRows([]string{"Cidr", "Start", "End"}))
+
+		soa, err := d.reverseZone(context.Background(), tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if soa != nil {
+			got = soa.Hdr.Name
+		}
+		if got != tt.want {
+			t.Errorf("reverseZone(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
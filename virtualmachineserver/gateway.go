@@ -0,0 +1,247 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	pb "github.com/achanno/sreapi/protobuf"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+// The gateway calls the servers in-process, which skips the interceptors grpc
+// runs for its own calls. gatewayServer and gatewayServerV2 wrap the servers
+// so REST requests go through the same interceptor as grpc requests.
+
+// intercept runs h through ic as grpc would for fullMethod
+func intercept[Req, Resp any](ctx context.Context, ic grpc.UnaryServerInterceptor, srv interface{}, fullMethod string, in Req, h func(context.Context, Req) (Resp, error)) (Resp, error) {
+	ctx = context.WithValue(ctx, transportKey{}, "gateway")
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+	resp, err := ic(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return h(ctx, req.(Req))
+	})
+	if err != nil {
+		var zero Resp
+		return zero, err
+	}
+	return resp.(Resp), nil
+}
+
+type gatewayServer struct {
+	srv pb.VirtualmachinesServer
+	ic  grpc.UnaryServerInterceptor
+}
+
+func (g *gatewayServer) List(ctx context.Context, in *pb.ListRequest) (*pb.ListResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.Virtualmachines/List", in, g.srv.List)
+}
+
+func (g *gatewayServer) Get(ctx context.Context, in *pb.GetRequest) (*pb.GetResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.Virtualmachines/Get", in, g.srv.Get)
+}
+
+func (g *gatewayServer) Create(ctx context.Context, in *pb.CreateRequest) (*pb.CreateResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.Virtualmachines/Create", in, g.srv.Create)
+}
+
+func (g *gatewayServer) Update(ctx context.Context, in *pb.UpdateRequest) (*pb.UpdateResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.Virtualmachines/Update", in, g.srv.Update)
+}
+
+func (g *gatewayServer) Delete(ctx context.Context, in *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.Virtualmachines/Delete", in, g.srv.Delete)
+}
+
+type gatewayServerV2 struct {
+	srv pbv2.VirtualmachinesServer
+	ic  grpc.UnaryServerInterceptor
+}
+
+func (g *gatewayServerV2) List(ctx context.Context, in *pbv2.ListVirtualmachinesRequest) (*pbv2.ListVirtualmachinesResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/List", in, g.srv.List)
+}
+
+func (g *gatewayServerV2) Get(ctx context.Context, in *pbv2.GetVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/Get", in, g.srv.Get)
+}
+
+func (g *gatewayServerV2) Create(ctx context.Context, in *pbv2.CreateVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/Create", in, g.srv.Create)
+}
+
+func (g *gatewayServerV2) Update(ctx context.Context, in *pbv2.UpdateVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/Update", in, g.srv.Update)
+}
+
+func (g *gatewayServerV2) Delete(ctx context.Context, in *pbv2.DeleteVirtualmachineRequest) (*empty.Empty, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/Delete", in, g.srv.Delete)
+}
+
+func (g *gatewayServerV2) BatchCreate(ctx context.Context, in *pbv2.BatchCreateVirtualmachinesRequest) (*pbv2.BatchCreateVirtualmachinesResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/BatchCreate", in, g.srv.BatchCreate)
+}
+
+func (g *gatewayServerV2) GetNamingTemplate(ctx context.Context, in *pbv2.GetNamingTemplateRequest) (*pbv2.NamingTemplate, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/GetNamingTemplate", in, g.srv.GetNamingTemplate)
+}
+
+func (g *gatewayServerV2) SetNamingTemplate(ctx context.Context, in *pbv2.SetNamingTemplateRequest) (*pbv2.NamingTemplate, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/SetNamingTemplate", in, g.srv.SetNamingTemplate)
+}
+
+func (g *gatewayServerV2) AllocateHostname(ctx context.Context, in *pbv2.AllocateHostnameRequest) (*pbv2.AllocateHostnameResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Virtualmachines/AllocateHostname", in, g.srv.AllocateHostname)
+}
+
+type gatewayIpam struct {
+	srv pbv2.IpamServer
+	ic  grpc.UnaryServerInterceptor
+}
+
+func (g *gatewayIpam) CreateSubnet(ctx context.Context, in *pbv2.CreateSubnetRequest) (*pbv2.Subnet, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/CreateSubnet", in, g.srv.CreateSubnet)
+}
+
+func (g *gatewayIpam) ListSubnets(ctx context.Context, in *pbv2.ListSubnetsRequest) (*pbv2.ListSubnetsResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/ListSubnets", in, g.srv.ListSubnets)
+}
+
+func (g *gatewayIpam) GetSubnet(ctx context.Context, in *pbv2.GetSubnetRequest) (*pbv2.Subnet, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/GetSubnet", in, g.srv.GetSubnet)
+}
+
+func (g *gatewayIpam) DeleteSubnet(ctx context.Context, in *pbv2.DeleteSubnetRequest) (*empty.Empty, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/DeleteSubnet", in, g.srv.DeleteSubnet)
+}
+
+func (g *gatewayIpam) GetSubnetUsage(ctx context.Context, in *pbv2.GetSubnetUsageRequest) (*pbv2.SubnetUsage, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/GetSubnetUsage", in, g.srv.GetSubnetUsage)
+}
+
+func (g *gatewayIpam) AllocateIP(ctx context.Context, in *pbv2.AllocateIPRequest) (*pbv2.Allocation, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/AllocateIP", in, g.srv.AllocateIP)
+}
+
+func (g *gatewayIpam) ReleaseIP(ctx context.Context, in *pbv2.ReleaseIPRequest) (*empty.Empty, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Ipam/ReleaseIP", in, g.srv.ReleaseIP)
+}
+
+type gatewayProjects struct {
+	srv pbv2.ProjectsServer
+	ic  grpc.UnaryServerInterceptor
+}
+
+func (g *gatewayProjects) CreateProject(ctx context.Context, in *pbv2.CreateProjectRequest) (*pbv2.Project, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Projects/CreateProject", in, g.srv.CreateProject)
+}
+
+func (g *gatewayProjects) GetProject(ctx context.Context, in *pbv2.GetProjectRequest) (*pbv2.Project, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Projects/GetProject", in, g.srv.GetProject)
+}
+
+func (g *gatewayProjects) ListProjects(ctx context.Context, in *pbv2.ListProjectsRequest) (*pbv2.ListProjectsResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Projects/ListProjects", in, g.srv.ListProjects)
+}
+
+func (g *gatewayProjects) UpdateProject(ctx context.Context, in *pbv2.UpdateProjectRequest) (*pbv2.Project, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Projects/UpdateProject", in, g.srv.UpdateProject)
+}
+
+func (g *gatewayProjects) DeleteProject(ctx context.Context, in *pbv2.DeleteProjectRequest) (*empty.Empty, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Projects/DeleteProject", in, g.srv.DeleteProject)
+}
+
+type gatewayRoles struct {
+	srv pbv2.RolesServer
+	ic  grpc.UnaryServerInterceptor
+}
+
+func (g *gatewayRoles) CreateRole(ctx context.Context, in *pbv2.CreateRoleRequest) (*pbv2.Role, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Roles/CreateRole", in, g.srv.CreateRole)
+}
+
+func (g *gatewayRoles) GetRole(ctx context.Context, in *pbv2.GetRoleRequest) (*pbv2.Role, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Roles/GetRole", in, g.srv.GetRole)
+}
+
+func (g *gatewayRoles) ListRoles(ctx context.Context, in *pbv2.ListRolesRequest) (*pbv2.ListRolesResponse, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Roles/ListRoles", in, g.srv.ListRoles)
+}
+
+func (g *gatewayRoles) UpdateRole(ctx context.Context, in *pbv2.UpdateRoleRequest) (*pbv2.Role, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Roles/UpdateRole", in, g.srv.UpdateRole)
+}
+
+func (g *gatewayRoles) DeleteRole(ctx context.Context, in *pbv2.DeleteRoleRequest) (*empty.Empty, error) {
+	return intercept(ctx, g.ic, g.srv, "/sreapi.v2.Roles/DeleteRole", in, g.srv.DeleteRole)
+}
+
+// httpHandler serves a plain http endpoint, returning the value to encode as
+// JSON, or as YAML when it is wrapped in yamlResponse
+type httpHandler func(ctx context.Context, r *http.Request) (interface{}, error)
+
+// yamlResponse is written by httpEndpoint as YAML
+type yamlResponse struct {
+	v interface{}
+}
+
+// httpEndpoint serves h outside the gateway for documents that are not
+// protobuf messages. Calls go through the same interceptors as grpc and REST
+// calls, as the method /sreapi.http/name, and errors are written as the
+// gateway writes them.
+func (s *Server) httpEndpoint(name string, h httpHandler) http.Handler {
+	info := &grpc.UnaryServerInfo{Server: s, FullMethod: "/sreapi.http/" + name}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// The stream collects the headers set by the interceptors
+		stream := &runtime.ServerTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(r.Context(), stream)
+		ctx = context.WithValue(ctx, transportKey{}, "http")
+
+		var resp interface{}
+		ctx, err := runtime.AnnotateIncomingContext(ctx, s.mux, r)
+		if err == nil {
+			resp, err = s.unary(ctx, r, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return h(ctx, req.(*http.Request))
+			})
+		}
+
+		for k, vs := range stream.Header() {
+			if name, ok := outgoingHeaderMatcher(k); ok {
+				for _, v := range vs {
+					w.Header().Add(name, v)
+				}
+			}
+		}
+		if err != nil {
+			_, outbound := runtime.MarshalerForRequest(s.mux, r)
+			runtime.HTTPError(ctx, s.mux, outbound, w, r, err)
+			return
+		}
+		if y, ok := resp.(yamlResponse); ok {
+			b, err := yaml.Marshal(y.v)
+			if err != nil {
+				runtime.HTTPError(ctx, s.mux, &runtime.JSONPb{}, w, r, status.Error(codes.Internal, err.Error()))
+				return
+			}
+			w.Header().Set("Content-Type", "application/yaml")
+			w.Write(b)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(resp)
+	})
+}
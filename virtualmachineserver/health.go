@@ -0,0 +1,83 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	dbPingInterval = 10 * time.Second
+	dbPingTimeout  = 2 * time.Second
+	// dbMigrateTimeout bounds creating the missing tables at start
+	dbMigrateTimeout = 30 * time.Second
+)
+
+// healthServices are reported by the grpc health service, "" is the server as a whole
+var healthServices = []string{
+	"",
+	"sreapi.Virtualmachines",
+	"sreapi.v2.Virtualmachines",
+	"sreapi.v2.Ipam",
+	"sreapi.v2.Projects",
+	"sreapi.v2.Roles",
+}
+
+func (s *Server) pingDB(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, dbPingTimeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+// watchDB keeps the grpc health status in line with the database until ctx is
+// done
+func (s *Server) watchDB(ctx context.Context) {
+	ticker := time.NewTicker(dbPingInterval)
+	defer ticker.Stop()
+
+	var last healthpb.HealthCheckResponse_ServingStatus
+	for {
+		st := healthpb.HealthCheckResponse_SERVING
+		if err := s.pingDB(ctx); err != nil {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+			if last != st {
+				s.logger.Error("database unreachable", "error", err)
+			}
+		} else if last == healthpb.HealthCheckResponse_NOT_SERVING {
+			s.logger.Info("database reachable again")
+		}
+		if last != st {
+			for _, svc := range healthServices {
+				s.health.SetServingStatus(svc, st)
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthzHandler reports the process is up
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
+// readyzHandler reports whether requests can be served, which needs the
+// database and the server not to be shutting down
+func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if s.shuttingDown.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	if err := s.pingDB(r.Context()); err != nil {
+		http.Error(w, "database unreachable: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
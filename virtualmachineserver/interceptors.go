@@ -0,0 +1,150 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthFunc authenticates a call to fullMethod. It returns the context the
+// call continues with, typically carrying the caller from WithIdentity, or an
+// Unauthenticated or PermissionDenied error to reject the call.
+type AuthFunc func(ctx context.Context, fullMethod string) (context.Context, error)
+
+type identityKey struct{}
+
+// WithIdentity records who is making the call, for use by an AuthFunc
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Identity returns the caller recorded by WithIdentity
+func Identity(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(identityKey{}).(string)
+	return id, ok
+}
+
+// unaryInterceptors is the pipeline every unary call goes through, over grpc
+// and through the gateway, outermost first. Tracing is not one of them, grpc
+// calls are traced by the server's stats handler and gateway calls by
+// otelhttp. Recovery runs inside logging and metrics so a panic is reported
+// like any other Internal error. Rate limiting follows auth so callers are
+// limited by identity. Interceptors from Options run last.
+func (s *Server) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	ics := []grpc.UnaryServerInterceptor{
+		s.loggingInterceptor,
+		s.metrics.interceptor,
+		recoveryInterceptor,
+	}
+	if s.opts.Auth != nil {
+		ics = append(ics, authInterceptor(s.opts.Auth))
+	}
+	ics = append(ics, s.limiter.interceptor, validationInterceptor)
+	return append(ics, s.opts.UnaryInterceptors...)
+}
+
+// streamInterceptors is the pipeline for streaming calls, outermost first
+func (s *Server) streamInterceptors() []grpc.StreamServerInterceptor {
+	ics := []grpc.StreamServerInterceptor{
+		s.streamLoggingInterceptor,
+		s.metrics.streamInterceptor,
+		streamRecoveryInterceptor,
+	}
+	if s.opts.Auth != nil {
+		ics = append(ics, streamAuthInterceptor(s.opts.Auth))
+	}
+	ics = append(ics, s.limiter.streamInterceptor)
+	return append(ics, s.opts.StreamInterceptors...)
+}
+
+// chainUnary combines interceptors into one, the first is outermost
+func chainUnary(ics ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		h := handler
+		for i := len(ics) - 1; i >= 0; i-- {
+			ic, next := ics[i], h
+			h = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return ic(ctx, req, info, next)
+			}
+		}
+		return h(ctx, req)
+	}
+}
+
+// serverStream lets stream interceptors replace the stream's context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// recovered turns a panic in a handler into an Internal error
+func recovered(ctx context.Context, p interface{}) error {
+	loggerFrom(ctx).Error("panic handling call", "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ctx, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func streamRecoveryInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ss.Context(), p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func authInterceptor(auth AuthFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := auth(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthInterceptor(auth AuthFunc) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := auth(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// validator is implemented by requests that can check themselves
+type validator interface {
+	Validate() error
+}
+
+// validationInterceptor rejects invalid requests with InvalidArgument before
+// they reach the handler
+func validationInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := validateRequest(req)
+	if v, ok := req.(validator); ok && err == nil {
+		err = v.Validate()
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+	return handler(ctx, req)
+}
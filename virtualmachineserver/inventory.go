@@ -0,0 +1,84 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achanno/sreapi/inventory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ansibleInventory serves the ansible dynamic inventory, or the hostvars of
+// one vm with ?host=, as an inventory script prints them for --list and --host
+func (s *Server) ansibleInventory(ctx context.Context, r *http.Request) (interface{}, error) {
+	if host := r.URL.Query().Get("host"); host != "" {
+		vm, err := s.store.getVM(ctx, host, false)
+		if err != nil {
+			return nil, err
+		}
+		return inventory.HostVars(vm), nil
+	}
+
+	vms, err := s.store.listVMs(ctx, vmFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return inventory.Ansible(vms), nil
+}
+
+// prometheusTargets serves the vms as prometheus http_sd targets, filtered by
+// ?project= and ?role= and scraped on ?port=
+func (s *Server) prometheusTargets(ctx context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	port := inventory.DefaultScrapePort
+	if p := q.Get("port"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, invalidField("port", "port must be a number from 1 to 65535")
+		}
+		port = n
+	}
+
+	vms, err := s.store.listVMs(ctx, vmFilter{Project: q.Get("project"), Role: q.Get("role")})
+	if err != nil {
+		return nil, err
+	}
+	return inventory.PrometheusTargets(vms, port), nil
+}
+
+// encNode classifies the vm named in the path, /enc/<hostname>, with the
+// mapping in Options.ENCMapping, read on every call so edits apply at once.
+// Puppet asks by certname, so a fully qualified name falls back to the vm
+// named by its first label. The classes of the vm's role are added to the
+// mapping's.
+func (s *Server) encNode(ctx context.Context, r *http.Request) (interface{}, error) {
+	hostname := strings.TrimPrefix(r.URL.Path, "/enc/")
+	if hostname == "" || strings.Contains(hostname, "/") {
+		return nil, invalidField("hostname", "want /enc/<hostname>")
+	}
+	m, err := inventory.LoadENCMapping(s.opts.ENCMapping)
+	if err != nil {
+		loggerFrom(ctx).Error("could not load enc mapping", "error", err)
+		return nil, status.Error(codes.FailedPrecondition, "enc mapping is not valid")
+	}
+
+	vm, err := s.store.getVM(ctx, hostname, false)
+	if short, _, ok := strings.Cut(hostname, "."); ok && status.Code(err) == codes.NotFound {
+		vm, err = s.store.getVM(ctx, short, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+	node := m.Classify(vm)
+	role, err := s.store.getRole(ctx, vm.Role)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	if role != nil {
+		node.AddClasses(role.Classes...)
+	}
+	return yamlResponse{node}, nil
+}
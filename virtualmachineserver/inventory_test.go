@@ -0,0 +1,17 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrometheusTargetsRejectsBadPort(t *testing.T) {
+	s := &Server{}
+	for _, port := range []string{"0", "65536", "-1", "http"} {
+		r := httptest.NewRequest("GET", "/sd/prometheus?port="+port, nil)
+		if _, err := s.prometheusTargets(context.Background(), r); violationField(err) != "port" {
+			t.Errorf("port %s: error = %v, want an invalid port", port, err)
+		}
+	}
+}
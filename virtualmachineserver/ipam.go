@@ -0,0 +1,286 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/netip"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// ipamServer manages subnets and the addresses vms get from them
+type ipamServer struct {
+	store *store
+}
+
+// CreateSubnet subnet
+func (s *ipamServer) CreateSubnet(ctx context.Context, in *pbv2.CreateSubnetRequest) (*pbv2.Subnet, error) {
+	loggerFrom(ctx).Info("creating subnet", "cidr", in.Subnet.Cidr)
+	if err := s.store.createSubnet(ctx, in.Subnet); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusCreated)
+	return in.Subnet, nil
+}
+
+// ListSubnets in address order
+func (s *ipamServer) ListSubnets(ctx context.Context, in *pbv2.ListSubnetsRequest) (*pbv2.ListSubnetsResponse, error) {
+	subnets, err := s.store.listSubnets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.ListSubnetsResponse{Subnets: subnets}, nil
+}
+
+// GetSubnet subnet
+func (s *ipamServer) GetSubnet(ctx context.Context, in *pbv2.GetSubnetRequest) (*pbv2.Subnet, error) {
+	return getSubnet(ctx, s.store.db, in.Cidr, false)
+}
+
+// DeleteSubnet subnet
+func (s *ipamServer) DeleteSubnet(ctx context.Context, in *pbv2.DeleteSubnetRequest) (*empty.Empty, error) {
+	loggerFrom(ctx).Info("deleting subnet", "cidr", in.Cidr)
+	if err := s.store.deleteSubnet(ctx, in.Cidr); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusNoContent)
+	return &empty.Empty{}, nil
+}
+
+// GetSubnetUsage reports how much of the subnet is allocated, and to whom
+func (s *ipamServer) GetSubnetUsage(ctx context.Context, in *pbv2.GetSubnetUsageRequest) (*pbv2.SubnetUsage, error) {
+	sn, err := getSubnet(ctx, s.store.db, in.Cidr, false)
+	if err != nil {
+		return nil, err
+	}
+	allocs, err := allocationsIn(ctx, s.store.db, sn)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &pbv2.SubnetUsage{Subnet: sn, Allocations: allocs}
+	p, ranges, gw, err := parseSubnet(sn)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range ranges {
+		usage.Size = addSat(usage.Size, r.size())
+		if gw.IsValid() && r.contains(gw) {
+			usage.Size--
+		}
+	}
+	for _, a := range allocs {
+		if addr, err := netip.ParseAddr(a.Address); err == nil && p.Contains(addr) && inRanges(ranges, addr) {
+			usage.Allocated++
+		}
+	}
+	if usage.Allocated < usage.Size {
+		usage.Free = usage.Size - usage.Allocated
+	}
+	return usage, nil
+}
+
+// AllocateIP gives the vm an address from the subnet
+func (s *ipamServer) AllocateIP(ctx context.Context, in *pbv2.AllocateIPRequest) (*pbv2.Allocation, error) {
+	var addr string
+	err := s.store.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		addr, err = allocateIP(ctx, tx, in.Hostname, in.Subnet, in.Address)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	loggerFrom(ctx).Info("allocated address", "hostname", in.Hostname, "subnet", in.Subnet, "address", addr)
+	return &pbv2.Allocation{Address: addr, Hostname: in.Hostname, Subnet: canonicalCidr(in.Subnet)}, nil
+}
+
+// ReleaseIP takes the address from the vm
+func (s *ipamServer) ReleaseIP(ctx context.Context, in *pbv2.ReleaseIPRequest) (*empty.Empty, error) {
+	loggerFrom(ctx).Info("releasing address", "hostname", in.Hostname, "address", in.Address)
+	if err := s.store.releaseIP(ctx, in.Hostname, in.Address); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// addrRange is the addresses from first to last, both included
+type addrRange struct {
+	first, last netip.Addr
+}
+
+func (r addrRange) contains(addr netip.Addr) bool {
+	return r.first.Compare(addr) <= 0 && addr.Compare(r.last) <= 0
+}
+
+// size is the number of addresses in r, at most math.MaxUint64
+func (r addrRange) size() uint64 {
+	n := new(big.Int).Sub(new(big.Int).SetBytes(r.last.AsSlice()), new(big.Int).SetBytes(r.first.AsSlice()))
+	n.Add(n, big.NewInt(1))
+	if !n.IsUint64() {
+		return math.MaxUint64
+	}
+	return n.Uint64()
+}
+
+func addSat(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+func inRanges(ranges []addrRange, addr netip.Addr) bool {
+	for _, r := range ranges {
+		if r.contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastAddr is the highest address in p
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// hostRange is the addresses of p that can be given to hosts. The first
+// address, the network or IPv6 subnet router anycast address, and the IPv4
+// broadcast address are left out unless p is too small to have them.
+func hostRange(p netip.Prefix) addrRange {
+	r := addrRange{p.Addr(), lastAddr(p)}
+	if p.Addr().Is4() && p.Bits() <= 30 {
+		r.first, r.last = r.first.Next(), r.last.Prev()
+	} else if p.Addr().Is6() && p.Bits() <= 126 {
+		r.first = r.first.Next()
+	}
+	return r
+}
+
+// parseSubnet checks sn, returning its prefix, the ranges addresses are
+// allocated from and its gateway if it has one
+func parseSubnet(sn *pbv2.Subnet) (netip.Prefix, []addrRange, netip.Addr, error) {
+	var gw netip.Addr
+	p, err := netip.ParsePrefix(sn.Cidr)
+	if err != nil {
+		return p, nil, gw, invalidField("subnet.cidr", fmt.Sprintf("%q is not a cidr like 10.0.0.0/24", sn.Cidr))
+	}
+	if p != p.Masked() {
+		return p, nil, gw, invalidField("subnet.cidr", fmt.Sprintf("%s has host bits set, did you mean %s?", sn.Cidr, p.Masked()))
+	}
+
+	if sn.Gateway != "" {
+		gw, err = netip.ParseAddr(sn.Gateway)
+		if err != nil || !p.Contains(gw) {
+			return p, nil, gw, invalidField("subnet.gateway", fmt.Sprintf("gateway %q is not an address in %s", sn.Gateway, p))
+		}
+	}
+
+	if len(sn.Pools) == 0 {
+		return p, []addrRange{hostRange(p)}, gw, nil
+	}
+	ranges := make([]addrRange, len(sn.Pools))
+	for i, pool := range sn.Pools {
+		field := fmt.Sprintf("subnet.pools[%d]", i)
+		first, ferr := netip.ParseAddr(pool.Start)
+		last, lerr := netip.ParseAddr(pool.End)
+		switch {
+		case ferr != nil || lerr != nil:
+			return p, nil, gw, invalidField(field, fmt.Sprintf("pool %s-%s is not a range of addresses", pool.Start, pool.End))
+		case !p.Contains(first) || !p.Contains(last):
+			return p, nil, gw, invalidField(field, fmt.Sprintf("pool %s-%s is not within %s", pool.Start, pool.End, p))
+		case last.Less(first):
+			return p, nil, gw, invalidField(field, fmt.Sprintf("pool %s-%s ends before it starts", pool.Start, pool.End))
+		}
+		ranges[i] = addrRange{first, last}
+		for j := 0; j < i; j++ {
+			if ranges[j].contains(first) || ranges[j].contains(last) || ranges[i].contains(ranges[j].first) {
+				return p, nil, gw, invalidField(field, fmt.Sprintf("pool %s-%s overlaps pool %s-%s", pool.Start, pool.End, sn.Pools[j].Start, sn.Pools[j].End))
+			}
+		}
+	}
+	return p, ranges, gw, nil
+}
+
+// overlapping is the first of cidrs that shares addresses with p, other than
+// p itself which is left for the duplicate key to report
+func overlapping(p netip.Prefix, cidrs []string) string {
+	for _, c := range cidrs {
+		if other, err := netip.ParsePrefix(c); err == nil && other != p && other.Overlaps(p) {
+			return c
+		}
+	}
+	return ""
+}
+
+// nextFree is the first address in ranges that is not the gateway or used
+func nextFree(ranges []addrRange, gw netip.Addr, used map[netip.Addr]bool) (netip.Addr, bool) {
+	for _, r := range ranges {
+		for addr := r.first; addr.IsValid() && addr.Compare(r.last) <= 0; addr = addr.Next() {
+			if addr != gw && !used[addr] {
+				return addr, true
+			}
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// requireCidr checks a cidr naming a subnet
+func requireCidr(field, cidr string) error {
+	if cidr == "" {
+		return invalidField(field, field+" is required")
+	}
+	if _, err := netip.ParsePrefix(cidr); err != nil {
+		return invalidField(field, fmt.Sprintf("%q is not a cidr like 10.0.0.0/24", cidr))
+	}
+	return nil
+}
+
+// validateIPAMRequest checks the ipam requests, it is run by
+// validationInterceptor
+func validateIPAMRequest(req interface{}) error {
+	switch in := req.(type) {
+	case *pbv2.CreateSubnetRequest:
+		if in.Subnet == nil {
+			return invalidField("subnet", "subnet is required")
+		}
+		_, _, _, err := parseSubnet(in.Subnet)
+		return err
+	case *pbv2.GetSubnetRequest:
+		return requireCidr("cidr", in.Cidr)
+	case *pbv2.DeleteSubnetRequest:
+		return requireCidr("cidr", in.Cidr)
+	case *pbv2.GetSubnetUsageRequest:
+		return requireCidr("cidr", in.Cidr)
+	case *pbv2.AllocateIPRequest:
+		if err := requireHostname(in.Hostname); err != nil {
+			return err
+		}
+		if err := requireCidr("subnet", in.Subnet); err != nil {
+			return err
+		}
+		if in.Address != "" {
+			if _, err := netip.ParseAddr(in.Address); err != nil {
+				return invalidField("address", fmt.Sprintf("%q is not an IPv4 or IPv6 address", in.Address))
+			}
+		}
+	case *pbv2.ReleaseIPRequest:
+		if err := requireHostname(in.Hostname); err != nil {
+			return err
+		}
+		if _, err := netip.ParseAddr(in.Address); err != nil {
+			return invalidField("address", fmt.Sprintf("%q is not an IPv4 or IPv6 address", in.Address))
+		}
+	}
+	return nil
+}
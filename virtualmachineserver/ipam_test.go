@@ -0,0 +1,165 @@
+package virtualmachineserver
+
+import (
+	"math"
+	"net/netip"
+	"strings"
+	"testing"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHostRange(t *testing.T) {
+	tests := []struct {
+		cidr        string
+		first, last string
+		size        uint64
+	}{
+		{"10.0.0.0/24", "10.0.0.1", "10.0.0.254", 254},
+		{"10.0.0.0/30", "10.0.0.1", "10.0.0.2", 2},
+		{"10.0.0.0/31", "10.0.0.0", "10.0.0.1", 2},
+		{"10.0.0.7/32", "10.0.0.7", "10.0.0.7", 1},
+		{"2001:db8::/126", "2001:db8::1", "2001:db8::3", 3},
+		{"2001:db8::/127", "2001:db8::", "2001:db8::1", 2},
+		{"2001:db8::/64", "2001:db8::1", "2001:db8::ffff:ffff:ffff:ffff", math.MaxUint64},
+		{"2001:db8::/32", "2001:db8::1", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", math.MaxUint64},
+	}
+	for _, tt := range tests {
+		r := hostRange(netip.MustParsePrefix(tt.cidr))
+		if r.first.String() != tt.first || r.last.String() != tt.last {
+			t.Errorf("hostRange(%s) = %s-%s, want %s-%s", tt.cidr, r.first, r.last, tt.first, tt.last)
+		}
+		if got := r.size(); got != tt.size {
+			t.Errorf("hostRange(%s).size() = %d, want %d", tt.cidr, got, tt.size)
+		}
+	}
+}
+
+func TestParseSubnet(t *testing.T) {
+	pools := func(ranges ...string) []*pbv2.Pool {
+		var ps []*pbv2.Pool
+		for _, r := range ranges {
+			start, end, _ := strings.Cut(r, "-")
+			ps = append(ps, &pbv2.Pool{Start: start, End: end})
+		}
+		return ps
+	}
+	tests := []struct {
+		name   string
+		subnet *pbv2.Subnet
+		ranges string
+		field  string
+	}{
+		{"whole subnet", &pbv2.Subnet{Cidr: "10.0.0.0/24"}, "10.0.0.1-10.0.0.254", ""},
+		{"pools", &pbv2.Subnet{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1", Pools: pools("10.0.0.10-10.0.0.19", "10.0.0.30-10.0.0.30")}, "10.0.0.10-10.0.0.19,10.0.0.30-10.0.0.30", ""},
+		{"ipv6", &pbv2.Subnet{Cidr: "2001:db8::/64", Pools: pools("2001:db8::10-2001:db8::ff")}, "2001:db8::10-2001:db8::ff", ""},
+		{"not a cidr", &pbv2.Subnet{Cidr: "10.0.0.0"}, "", "subnet.cidr"},
+		{"host bits", &pbv2.Subnet{Cidr: "10.0.0.1/24"}, "", "subnet.cidr"},
+		{"gateway outside", &pbv2.Subnet{Cidr: "10.0.0.0/24", Gateway: "10.0.1.1"}, "", "subnet.gateway"},
+		{"gateway not an address", &pbv2.Subnet{Cidr: "10.0.0.0/24", Gateway: "gw"}, "", "subnet.gateway"},
+		{"pool not addresses", &pbv2.Subnet{Cidr: "10.0.0.0/24", Pools: pools("a-b")}, "", "subnet.pools[0]"},
+		{"pool outside", &pbv2.Subnet{Cidr: "10.0.0.0/24", Pools: pools("10.0.0.10-10.0.1.10")}, "", "subnet.pools[0]"},
+		{"pool backwards", &pbv2.Subnet{Cidr: "10.0.0.0/24", Pools: pools("10.0.0.20-10.0.0.10")}, "", "subnet.pools[0]"},
+		{"pools overlap", &pbv2.Subnet{Cidr: "10.0.0.0/24", Pools: pools("10.0.0.10-10.0.0.20", "10.0.0.20-10.0.0.30")}, "", "subnet.pools[1]"},
+		{"pool inside pool", &pbv2.Subnet{Cidr: "10.0.0.0/24", Pools: pools("10.0.0.15-10.0.0.16", "10.0.0.10-10.0.0.20")}, "", "subnet.pools[1]"},
+	}
+	for _, tt := range tests {
+		_, ranges, _, err := parseSubnet(tt.subnet)
+		if tt.field != "" {
+			if got := violationField(err); got != tt.field {
+				t.Errorf("%s: parseSubnet error = %v, want a violation of %s", tt.name, err, tt.field)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: parseSubnet error = %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, r := range ranges {
+			got = append(got, r.first.String()+"-"+r.last.String())
+		}
+		if strings.Join(got, ",") != tt.ranges {
+			t.Errorf("%s: parseSubnet ranges = %s, want %s", tt.name, strings.Join(got, ","), tt.ranges)
+		}
+	}
+}
+
+// violationField is the field of the first BadRequest violation in err
+func violationField(err error) string {
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument {
+		return ""
+	}
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok && len(br.FieldViolations) > 0 {
+			return br.FieldViolations[0].Field
+		}
+	}
+	return ""
+}
+
+func TestOverlapping(t *testing.T) {
+	cidrs := []string{"10.0.0.0/24", "10.1.0.0/16", "2001:db8::/64"}
+	tests := []struct {
+		cidr, want string
+	}{
+		{"10.0.1.0/24", ""},
+		{"10.0.0.0/24", ""},
+		{"10.0.0.128/25", "10.0.0.0/24"},
+		{"10.0.0.0/8", "10.0.0.0/24"},
+		{"10.1.2.0/24", "10.1.0.0/16"},
+		{"2001:db8::/48", "2001:db8::/64"},
+		{"2001:db8:1::/64", ""},
+	}
+	for _, tt := range tests {
+		if got := overlapping(netip.MustParsePrefix(tt.cidr), cidrs); got != tt.want {
+			t.Errorf("overlapping(%s) = %q, want %q", tt.cidr, got, tt.want)
+		}
+	}
+}
+
+func TestNextFree(t *testing.T) {
+	addr := netip.MustParseAddr
+	ranges := []addrRange{
+		{addr("10.0.0.1"), addr("10.0.0.3")},
+		{addr("10.0.0.10"), addr("10.0.0.10")},
+	}
+	tests := []struct {
+		name string
+		gw   netip.Addr
+		used []string
+		want string
+	}{
+		{"empty", netip.Addr{}, nil, "10.0.0.1"},
+		{"skips the gateway", addr("10.0.0.1"), nil, "10.0.0.2"},
+		{"fills holes", netip.Addr{}, []string{"10.0.0.1", "10.0.0.3"}, "10.0.0.2"},
+		{"next range", addr("10.0.0.2"), []string{"10.0.0.1", "10.0.0.3"}, "10.0.0.10"},
+		{"full", netip.Addr{}, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.10"}, ""},
+	}
+	for _, tt := range tests {
+		used := map[netip.Addr]bool{}
+		for _, u := range tt.used {
+			used[addr(u)] = true
+		}
+		got, ok := nextFree(ranges, tt.gw, used)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: nextFree = %s, want none", tt.name, got)
+			}
+			continue
+		}
+		if !ok || got.String() != tt.want {
+			t.Errorf("%s: nextFree = %s, %v, want %s", tt.name, got, ok, tt.want)
+		}
+	}
+
+	// The last address of the address space ends the range
+	last := addr("255.255.255.255")
+	if got, ok := nextFree([]addrRange{{last, last}}, netip.Addr{}, map[netip.Addr]bool{last: true}); ok {
+		t.Errorf("nextFree at the end of the address space = %s, want none", got)
+	}
+}
@@ -0,0 +1,108 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDHeader is read from incoming metadata and echoed back in the response headers
+const requestIDHeader = "x-request-id"
+
+type loggerKey struct{}
+
+// loggerFrom returns the logger for the call in ctx, tagged with its request id
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestID returns the x-request-id sent by the client or a new one
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get(requestIDHeader); len(ids) > 0 && ids[0] != "" {
+		return ids[0]
+	}
+	return newRequestID()
+}
+
+// startCallLog tags the call with a request id, returns it to the client and
+// puts the call's logger, derived from logger, in ctx
+func startCallLog(ctx context.Context, logger *slog.Logger, fullMethod string) (context.Context, *slog.Logger) {
+	id := requestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+
+	l := logger.With("request_id", id, "method", fullMethod, "transport", transport(ctx))
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		l = l.With("trace_id", sc.TraceID().String())
+	}
+	return context.WithValue(ctx, loggerKey{}, l), l
+}
+
+// endCallLog logs the outcome of the call
+func endCallLog(l *slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []any{"code", code.String(), "duration_seconds", time.Since(start).Seconds()}
+	switch code {
+	case codes.OK:
+		l.Info("call finished", attrs...)
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		l.Error("call failed", append(attrs, "error", err)...)
+	default:
+		l.Warn("call failed", append(attrs, "error", err)...)
+	}
+}
+
+func (s *Server) loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx, l := startCallLog(ctx, s.logger, info.FullMethod)
+	resp, err := handler(ctx, req)
+	endCallLog(l, start, err)
+	return resp, err
+}
+
+func (s *Server) streamLoggingInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, l := startCallLog(ss.Context(), s.logger, info.FullMethod)
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	endCallLog(l, start, err)
+	return err
+}
+
+// incomingHeaderMatcher passes x-request-id from REST clients to the handlers
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, requestIDHeader) {
+		return requestIDHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher returns x-request-id and retry-after to REST clients
+// as plain headers
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case requestIDHeader:
+		return "X-Request-Id", true
+	case retryAfterHeader:
+		return "Retry-After", true
+	}
+	return "Grpc-Metadata-" + key, true
+}
@@ -0,0 +1,155 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const inventoryQueryTimeout = 5 * time.Second
+
+var inventoryVMs = prometheus.NewDesc(
+	"sreapi_inventory_vms",
+	"VMs in the inventory, by project and role.",
+	[]string{"project", "role"}, nil,
+)
+
+// metrics are the collectors of one Server
+type metrics struct {
+	rpcRequests  *prometheus.CounterVec
+	rpcDuration  *prometheus.HistogramVec
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// newMetrics registers the rpc, gateway, db pool and inventory metrics for db
+// with reg
+func newMetrics(reg prometheus.Registerer, db *sql.DB, logger *slog.Logger) (*metrics, error) {
+	m := &metrics{
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sreapi",
+			Name:      "rpc_requests_total",
+			Help:      "RPCs handled, by transport, service, method and status code.",
+		}, []string{"transport", "service", "method", "code"}),
+
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sreapi",
+			Name:      "rpc_duration_seconds",
+			Help:      "Time taken to handle RPCs, by transport, service and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"transport", "service", "method"}),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sreapi",
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served by the REST gateway, by method and status code.",
+		}, []string{"method", "code"}),
+
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sreapi",
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to serve REST gateway requests, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.rpcRequests, m.rpcDuration, m.httpRequests, m.httpDuration,
+		collectors.NewDBStatsCollector(db, "sreapi"),
+		inventoryCollector{db: db, logger: logger},
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// inventoryCollector counts vms per project and role on every scrape
+type inventoryCollector struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func (inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inventoryVMs
+}
+
+func (c inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryQueryTimeout)
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, "SELECT Project, Role, COUNT(*) FROM vm GROUP BY Project, Role")
+	if err != nil {
+		// Skip the inventory rather than fail the whole scrape while the db is down
+		c.logger.Error("error counting vms", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var project, role string
+		var count float64
+		if err := rows.Scan(&project, &role, &count); err != nil {
+			c.logger.Error("error scanning vm count", "error", err)
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(inventoryVMs, prometheus.GaugeValue, count, project, role)
+	}
+}
+
+type transportKey struct{}
+
+// transport reports whether a call came in over grpc, through the gateway or
+// to one of the plain http endpoints
+func transport(ctx context.Context) string {
+	if t, ok := ctx.Value(transportKey{}).(string); ok {
+		return t
+	}
+	return "grpc"
+}
+
+// splitMethod splits /package.Service/Method into service and method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
+
+// interceptor records rpcRequests and rpcDuration
+func (m *metrics) interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	t := transport(ctx)
+	service, method := splitMethod(info.FullMethod)
+	m.rpcRequests.WithLabelValues(t, service, method, status.Code(err).String()).Inc()
+	m.rpcDuration.WithLabelValues(t, service, method).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+func (m *metrics) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+
+	service, method := splitMethod(info.FullMethod)
+	m.rpcRequests.WithLabelValues("grpc", service, method, status.Code(err).String()).Inc()
+	m.rpcDuration.WithLabelValues("grpc", service, method).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// instrumentHTTP records httpRequests and httpDuration for h
+func (m *metrics) instrumentHTTP(h http.Handler) http.Handler {
+	return promhttp.InstrumentHandlerDuration(m.httpDuration, promhttp.InstrumentHandlerCounter(m.httpRequests, h))
+}
@@ -0,0 +1,157 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+// defaultNamingTemplate names the vms of projects without a template
+const defaultNamingTemplate = "{project}-{role}-{nn}"
+
+// templateLiteral is what the text around a template's placeholders may
+// contain, anything that can be in a hostname
+var templateLiteral = regexp.MustCompile(`^[A-Za-z0-9.\-]*$`)
+
+// hostnameLabel is what each dot separated part of a hostname may look like
+var hostnameLabel = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// validHostname reports whether name is a valid hostname, as in RFC 1123
+func validHostname(name string) bool {
+	if len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// expandTemplate fills in project and role, returning the hostname parts
+// around the number and the digits it is padded to. Projects and roles from
+// before names were checked may hold characters hostnames cannot, so the
+// hostnames it gives are checked too.
+func expandTemplate(tmpl, project, role string) (prefix, suffix string, width int, err error) {
+	var b strings.Builder
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			if !templateLiteral.MatchString(rest) {
+				return "", "", 0, fmt.Errorf("%q may only hold letters, digits, '-' and '.' outside placeholders", rest)
+			}
+			b.WriteString(rest)
+			break
+		}
+		if !templateLiteral.MatchString(rest[:open]) {
+			return "", "", 0, fmt.Errorf("%q may only hold letters, digits, '-' and '.' outside placeholders", rest[:open])
+		}
+		b.WriteString(rest[:open])
+		closing := strings.IndexByte(rest[open:], '}')
+		if rest[open] == '}' || closing < 0 {
+			return "", "", 0, fmt.Errorf("unbalanced braces in %q", tmpl)
+		}
+		name := rest[open+1 : open+closing]
+		rest = rest[open+closing+1:]
+
+		switch {
+		case name == "project":
+			b.WriteString(project)
+		case name == "role":
+			b.WriteString(role)
+		case name != "" && strings.Trim(name, "n") == "":
+			if width > 0 {
+				return "", "", 0, fmt.Errorf("%q has more than one number placeholder", tmpl)
+			}
+			width = len(name)
+			prefix = b.String()
+			b.Reset()
+		default:
+			return "", "", 0, fmt.Errorf("unknown placeholder {%s}, want {project}, {role} or {nn}", name)
+		}
+	}
+	if width == 0 {
+		return "", "", 0, fmt.Errorf("%q has no number placeholder like {nn}", tmpl)
+	}
+	if example := numberedHostname(prefix, b.String(), width, 1); !validHostname(example) {
+		return "", "", 0, fmt.Errorf("%q gives hostnames like %q for project %q and role %q, which are not valid hostnames", tmpl, example, project, role)
+	}
+	return prefix, b.String(), width, nil
+}
+
+// hostnameNumber returns the number in hostname when it was named by the
+// template expanded to prefix and suffix
+func hostnameNumber(hostname, prefix, suffix string) (int, bool) {
+	if len(hostname) <= len(prefix)+len(suffix) || !strings.HasPrefix(hostname, prefix) || !strings.HasSuffix(hostname, suffix) {
+		return 0, false
+	}
+	digits := hostname[len(prefix) : len(hostname)-len(suffix)]
+	if strings.Trim(digits, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
+
+// numberedHostname puts n, padded with zeros to width digits, between prefix
+// and suffix. Numbers too long for width are written out in full.
+func numberedHostname(prefix, suffix string, width, n int) string {
+	return fmt.Sprintf("%s%0*d%s", prefix, width, n, suffix)
+}
+
+// lowestFree is the lowest number from 1 up that is not used
+func lowestFree(used map[int]bool) int {
+	n := 1
+	for used[n] {
+		n++
+	}
+	return n
+}
+
+func checkNamingTemplate(field, tmpl string) error {
+	if tmpl == "" {
+		return invalidField(field, "template is required")
+	}
+	if _, _, _, err := expandTemplate(tmpl, "project", "role"); err != nil {
+		return invalidField(field, err.Error())
+	}
+	return nil
+}
+
+// GetNamingTemplate of a project, the default one when it has none
+func (s *vmServerV2) GetNamingTemplate(ctx context.Context, in *pbv2.GetNamingTemplateRequest) (*pbv2.NamingTemplate, error) {
+	if _, err := s.store.getProject(ctx, in.Project); err != nil {
+		return nil, err
+	}
+	tmpl, err := namingTemplate(ctx, s.store.db, in.Project)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.NamingTemplate{Project: in.Project, Template: tmpl}, nil
+}
+
+// SetNamingTemplate of a project
+func (s *vmServerV2) SetNamingTemplate(ctx context.Context, in *pbv2.SetNamingTemplateRequest) (*pbv2.NamingTemplate, error) {
+	loggerFrom(ctx).Info("setting naming template", "project", in.NamingTemplate.Project, "template", in.NamingTemplate.Template)
+	if err := s.store.setNamingTemplate(ctx, in.NamingTemplate.Project, in.NamingTemplate.Template); err != nil {
+		return nil, err
+	}
+	return in.NamingTemplate, nil
+}
+
+// AllocateHostname reserves the project's next free hostname for the caller
+// holding the token it returns
+func (s *vmServerV2) AllocateHostname(ctx context.Context, in *pbv2.AllocateHostnameRequest) (*pbv2.AllocateHostnameResponse, error) {
+	hostname, token, err := s.store.allocateHostname(ctx, in.Project, in.Role)
+	if err != nil {
+		return nil, err
+	}
+	loggerFrom(ctx).Info("reserved hostname", "hostname", hostname, "project", in.Project, "role", in.Role)
+	return &pbv2.AllocateHostnameResponse{Hostname: hostname, ReservationToken: token}, nil
+}
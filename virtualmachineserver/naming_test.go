@@ -0,0 +1,222 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl           string
+		prefix, suffix string
+		width          int
+		err            string
+	}{
+		{tmpl: defaultNamingTemplate, prefix: "billing-web-", width: 2},
+		{tmpl: "{role}{nnnn}.{project}", prefix: "web", suffix: ".billing", width: 4},
+		{tmpl: "vm{n}", prefix: "vm", width: 1},
+		{tmpl: "{project}-{role}", err: "no number placeholder"},
+		{tmpl: "{nn}-{nn}", err: "more than one number placeholder"},
+		{tmpl: "{host}-{nn}", err: "unknown placeholder {host}"},
+		{tmpl: "{}-{nn}", err: "unknown placeholder {}"},
+		{tmpl: "{project-{nn}", err: "unknown placeholder"},
+		{tmpl: "{nn", err: "unbalanced braces"},
+		{tmpl: "}{nn}", err: "unbalanced braces"},
+		{tmpl: "web_{nn}", err: "may only hold letters"},
+		{tmpl: "{nn}_x", err: "may only hold letters"},
+		{tmpl: "-{nn}", err: "not valid hostnames"},
+		{tmpl: "{nn}.", err: "not valid hostnames"},
+		{tmpl: "{project}..{nn}", err: "not valid hostnames"},
+		{tmpl: strings.Repeat("x", 62) + "{nn}", err: "not valid hostnames"},
+		{tmpl: strings.Repeat("x", 61) + "{nn}", prefix: strings.Repeat("x", 61), width: 2},
+		{tmpl: "{project}_{role}-{nn}", err: "may only hold letters"},
+	}
+	for _, tt := range tests {
+		prefix, suffix, width, err := expandTemplate(tt.tmpl, "billing", "web")
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expandTemplate(%q) error = %v, want %q", tt.tmpl, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expandTemplate(%q) error = %v", tt.tmpl, err)
+			continue
+		}
+		if prefix != tt.prefix || suffix != tt.suffix || width != tt.width {
+			t.Errorf("expandTemplate(%q) = %q, %q, %d, want %q, %q, %d", tt.tmpl, prefix, suffix, width, tt.prefix, tt.suffix, tt.width)
+		}
+	}
+}
+
+func TestExpandTemplateChecksNames(t *testing.T) {
+	// projects and roles from before names were checked
+	for _, names := range [][2]string{{"bill_ing", "web"}, {"billing", "web server"}, {"billing", "-web"}, {"billing.", "web"}} {
+		_, _, _, err := expandTemplate("{role}-{project}-{nn}", names[0], names[1])
+		if err == nil || !strings.Contains(err.Error(), "not valid hostnames") {
+			t.Errorf("expandTemplate with project %q and role %q error = %v, want invalid hostnames", names[0], names[1], err)
+		}
+	}
+	if _, _, _, err := expandTemplate("{project}.{role}-{nn}", "Billing", "web"); err != nil {
+		t.Errorf("expandTemplate error = %v", err)
+	}
+}
+
+func TestHostnameNumber(t *testing.T) {
+	tests := []struct {
+		hostname string
+		n        int
+		ok       bool
+	}{
+		{"billing-web-01", 1, true},
+		{"billing-web-0042", 42, true},
+		{"billing-web-123", 123, true},
+		{"billing-web-", 0, false},
+		{"billing-web-1a", 0, false},
+		{"billing-db-01", 0, false},
+		{"billing-web-01.old", 0, false},
+	}
+	for _, tt := range tests {
+		n, ok := hostnameNumber(tt.hostname, "billing-web-", "")
+		if n != tt.n || ok != tt.ok {
+			t.Errorf("hostnameNumber(%q) = %d, %v, want %d, %v", tt.hostname, n, ok, tt.n, tt.ok)
+		}
+	}
+
+	if n, ok := hostnameNumber("web07.billing", "web", ".billing"); n != 7 || !ok {
+		t.Errorf("hostnameNumber with a suffix = %d, %v, want 7, true", n, ok)
+	}
+}
+
+func TestNumberedHostname(t *testing.T) {
+	tests := []struct {
+		width, n int
+		want     string
+	}{
+		{2, 1, "web-01.b"},
+		{2, 99, "web-99.b"},
+		{2, 100, "web-100.b"},
+		{4, 7, "web-0007.b"},
+		{1, 12, "web-12.b"},
+	}
+	for _, tt := range tests {
+		if got := numberedHostname("web-", ".b", tt.width, tt.n); got != tt.want {
+			t.Errorf("numberedHostname(%d, %d) = %q, want %q", tt.width, tt.n, got, tt.want)
+		}
+		// Names it makes are read back as the same number
+		if n, ok := hostnameNumber(numberedHostname("web-", ".b", tt.width, tt.n), "web-", ".b"); !ok || n != tt.n {
+			t.Errorf("hostnameNumber of numberedHostname(%d, %d) = %d, %v", tt.width, tt.n, n, ok)
+		}
+	}
+}
+
+func TestLowestFree(t *testing.T) {
+	tests := []struct {
+		used []int
+		want int
+	}{
+		{nil, 1},
+		{[]int{1, 2, 3}, 4},
+		{[]int{2, 3}, 1},
+		{[]int{1, 3}, 2},
+		{[]int{0, 1}, 2},
+	}
+	for _, tt := range tests {
+		used := map[int]bool{}
+		for _, n := range tt.used {
+			used[n] = true
+		}
+		if got := lowestFree(used); got != tt.want {
+			t.Errorf("lowestFree(%v) = %d, want %d", tt.used, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNamingTemplate(t *testing.T) {
+	for tmpl, valid := range map[string]bool{
+		defaultNamingTemplate: true,
+		"":                    false,
+		"{project}":           false,
+	} {
+		if err := checkNamingTemplate("template", tmpl); (err == nil) != valid {
+			t.Errorf("checkNamingTemplate(%q) = %v, want valid %v", tmpl, err, valid)
+		}
+	}
+}
+
+func TestGetNamingTemplate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := regexp.QuoteMeta
+	columns := []string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}
+
+	mock.ExpectQuery(q(projectColumns + " WHERE p.Name = ?")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("billing", "", "", "", "", 0))
+	mock.ExpectQuery(q("SELECT Template FROM naming_templates WHERE Project = ?")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Template"}))
+	mock.ExpectQuery(q(projectColumns + " WHERE p.Name = ?")).WithArgs("nope").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	s := &vmServerV2{store: &store{db: db}}
+	tmpl, err := s.GetNamingTemplate(context.Background(), &pbv2.GetNamingTemplateRequest{Project: "billing"})
+	if err != nil || tmpl.Template != defaultNamingTemplate {
+		t.Errorf("GetNamingTemplate(billing) = %v, %v, want the default template", tmpl, err)
+	}
+	if _, err := s.GetNamingTemplate(context.Background(), &pbv2.GetNamingTemplateRequest{Project: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetNamingTemplate(nope) error = %v, want NotFound", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClaimReservation(t *testing.T) {
+	vm := &pbv2.Virtualmachine{Hostname: "billing-web-01", Project: "billing", Role: "web"}
+	tests := []struct {
+		name     string
+		held     []string
+		token    string
+		claimed  bool
+		reserved bool
+	}{
+		{"not reserved", nil, "", true, false},
+		{"own token", []string{"billing", "web", "secret"}, "secret", true, false},
+		{"no token", []string{"billing", "web", "secret"}, "", false, true},
+		{"other token", []string{"billing", "web", "secret"}, "guess", false, true},
+		{"other role", []string{"billing", "db", "secret"}, "secret", false, true},
+	}
+	for _, tt := range tests {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := sqlmock.NewRows([]string{"Project", "Role", "Token"})
+		if tt.held != nil {
+			rows.AddRow(tt.held[0], tt.held[1], tt.held[2])
+		}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT Project, Role, Token FROM hostname_reservations WHERE Hostname = ?")).WithArgs(vm.Hostname).WillReturnRows(rows)
+		if tt.claimed {
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM hostname_reservations WHERE Hostname = ?")).WithArgs(vm.Hostname).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		err = claimReservation(context.Background(), db, vm, tt.token)
+		if (err == nil) != tt.claimed || (violations(err) == "RESERVED:vms/billing-web-01") != tt.reserved {
+			t.Errorf("%s: claimReservation error = %v, want claimed %v", tt.name, err, tt.claimed)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,139 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// dnsLabel is what new project and role names may look like, so they can
+// be part of hostnames
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// maxDescription bounds project and role descriptions
+const maxDescription = 4096
+
+// projectServer manages the projects vms are created in
+type projectServer struct {
+	store *store
+}
+
+// CreateProject project
+func (s *projectServer) CreateProject(ctx context.Context, in *pbv2.CreateProjectRequest) (*pbv2.Project, error) {
+	loggerFrom(ctx).Info("creating project", "name", in.Project.Name, "owner_team", in.Project.OwnerTeam)
+	if err := s.store.createProject(ctx, in.Project); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusCreated)
+	return in.Project, nil
+}
+
+// GetProject project
+func (s *projectServer) GetProject(ctx context.Context, in *pbv2.GetProjectRequest) (*pbv2.Project, error) {
+	return s.store.getProject(ctx, in.Name)
+}
+
+// ListProjects by name
+func (s *projectServer) ListProjects(ctx context.Context, in *pbv2.ListProjectsRequest) (*pbv2.ListProjectsResponse, error) {
+	projects, err := s.store.listProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.ListProjectsResponse{Projects: projects}, nil
+}
+
+// UpdateProject project, only the fields in update_mask are changed when it
+// is set
+func (s *projectServer) UpdateProject(ctx context.Context, in *pbv2.UpdateProjectRequest) (*pbv2.Project, error) {
+	paths := in.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"owner_team", "contact", "cost_center", "description"}
+	}
+	update := func(p *pbv2.Project) error {
+		for _, path := range paths {
+			switch path {
+			case "owner_team":
+				p.OwnerTeam = in.Project.OwnerTeam
+			case "contact":
+				p.Contact = in.Project.Contact
+			case "cost_center":
+				p.CostCenter = in.Project.CostCenter
+			case "description":
+				p.Description = in.Project.Description
+			case "name", "vm_count":
+				return invalidField("update_mask", fmt.Sprintf("%s cannot be updated", path))
+			default:
+				return invalidField("update_mask", fmt.Sprintf("unknown field %q", path))
+			}
+		}
+		if err := checkProjectFields(p, "project"); err != nil {
+			return err
+		}
+		loggerFrom(ctx).Info("updating project", "name", p.Name, "owner_team", p.OwnerTeam)
+		return nil
+	}
+	return s.store.updateProject(ctx, in.Name, update)
+}
+
+// DeleteProject project, which must be empty
+func (s *projectServer) DeleteProject(ctx context.Context, in *pbv2.DeleteProjectRequest) (*empty.Empty, error) {
+	loggerFrom(ctx).Info("deleting project", "name", in.Name)
+	if err := s.store.deleteProject(ctx, in.Name); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusNoContent)
+	return &empty.Empty{}, nil
+}
+
+// checkProjectFields validates everything in p but its name
+func checkProjectFields(p *pbv2.Project, field string) error {
+	for _, f := range []struct{ name, value string }{
+		{"owner_team", p.OwnerTeam},
+		{"contact", p.Contact},
+		{"cost_center", p.CostCenter},
+	} {
+		if len(f.value) > 255 {
+			return invalidField(field+"."+f.name, fmt.Sprintf("%s is longer than 255 characters", f.name))
+		}
+	}
+	if len(p.Description) > maxDescription {
+		return invalidField(field+".description", fmt.Sprintf("description is longer than %d characters", maxDescription))
+	}
+	return nil
+}
+
+// validateProjectRequest checks the project requests, it is run by
+// validationInterceptor
+func validateProjectRequest(req interface{}) error {
+	switch in := req.(type) {
+	case *pbv2.CreateProjectRequest:
+		switch {
+		case in.Project == nil:
+			return invalidField("project", "project is required")
+		case in.Project.Name == "":
+			return invalidField("project.name", "name is required")
+		case !dnsLabel.MatchString(in.Project.Name):
+			return invalidField("project.name", fmt.Sprintf("project name %q must be 1 to 63 lower case letters, digits or '-', starting and ending with a letter or digit", in.Project.Name))
+		}
+		return checkProjectFields(in.Project, "project")
+	case *pbv2.GetProjectRequest:
+		return requireProject("name", in.Name)
+	case *pbv2.UpdateProjectRequest:
+		if err := requireProject("name", in.Name); err != nil {
+			return err
+		}
+		if in.Project == nil {
+			return invalidField("project", "project is required")
+		}
+		if in.Project.Name != "" && in.Project.Name != in.Name {
+			return invalidField("project.name", "projects cannot be renamed")
+		}
+	case *pbv2.DeleteProjectRequest:
+		return requireProject("name", in.Name)
+	}
+	return nil
+}
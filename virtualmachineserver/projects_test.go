@@ -0,0 +1,152 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestValidateProjectRequest(t *testing.T) {
+	long := strings.Repeat("x", 256)
+	tests := []struct {
+		req   interface{}
+		field string
+	}{
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "billing", OwnerTeam: "payments"}}, ""},
+		{&pbv2.CreateProjectRequest{}, "project"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{}}, "project.name"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "Billing"}}, "project.name"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "bill_ing"}}, "project.name"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "billing-"}}, "project.name"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "billing", Contact: long}}, "project.contact"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "billing", CostCenter: long}}, "project.cost_center"},
+		{&pbv2.CreateProjectRequest{Project: &pbv2.Project{Name: "billing", Description: strings.Repeat("x", maxDescription+1)}}, "project.description"},
+		{&pbv2.GetProjectRequest{}, "name"},
+		{&pbv2.DeleteProjectRequest{}, "name"},
+		{&pbv2.UpdateProjectRequest{Name: "billing", Project: &pbv2.Project{OwnerTeam: "payments"}}, ""},
+		{&pbv2.UpdateProjectRequest{Name: "billing", Project: &pbv2.Project{Name: "billing"}}, ""},
+		{&pbv2.UpdateProjectRequest{Project: &pbv2.Project{}}, "name"},
+		{&pbv2.UpdateProjectRequest{Name: "billing"}, "project"},
+		{&pbv2.UpdateProjectRequest{Name: "billing", Project: &pbv2.Project{Name: "shop"}}, "project.name"},
+	}
+	for _, tt := range tests {
+		err := validateProjectRequest(tt.req)
+		if got := violationField(err); got != tt.field || (err == nil) != (tt.field == "") {
+			t.Errorf("validateProjectRequest(%v) = %v, want field %q", tt.req, err, tt.field)
+		}
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := regexp.QuoteMeta
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(q(projectColumns + " WHERE p.Name = ? FOR UPDATE")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}).
+			AddRow("billing", "payments", "pay@example.com", "cc-1", "bills", 3))
+	mock.ExpectExec(q("UPDATE projects SET OwnerTeam=?, Contact=?, CostCenter=?, Description=? WHERE Name = ?")).
+		WithArgs("finance", "pay@example.com", "cc-1", "bills", "billing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	s := &projectServer{store: &store{db: db}}
+	p, err := s.UpdateProject(context.Background(), &pbv2.UpdateProjectRequest{
+		Name:       "billing",
+		Project:    &pbv2.Project{OwnerTeam: "finance"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"owner_team"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.OwnerTeam != "finance" || p.Contact != "pay@example.com" || p.VmCount != 3 {
+		t.Errorf("UpdateProject = %v, want only owner_team changed", p)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateProjectRejectsMask(t *testing.T) {
+	for _, path := range []string{"name", "vm_count", "owner"} {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(projectColumns)).WithArgs("billing").
+			WillReturnRows(sqlmock.NewRows([]string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}).
+				AddRow("billing", "payments", "", "", "", 0))
+		mock.ExpectRollback()
+
+		s := &projectServer{store: &store{db: db}}
+		_, err = s.UpdateProject(context.Background(), &pbv2.UpdateProjectRequest{
+			Name:       "billing",
+			Project:    &pbv2.Project{},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+		})
+		if violationField(err) != "update_mask" {
+			t.Errorf("update_mask %s: error = %v, want an invalid update_mask", path, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	}
+}
+
+func TestUpdateMissingProject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(projectColumns)).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}))
+	mock.ExpectRollback()
+
+	s := &projectServer{store: &store{db: db}}
+	_, err = s.UpdateProject(context.Background(), &pbv2.UpdateProjectRequest{Name: "billing", Project: &pbv2.Project{}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("UpdateProject error = %v, want NotFound", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteProjectWithVMs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(projectColumns + " WHERE p.Name = ? FOR UPDATE")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}).
+			AddRow("billing", "payments", "", "", "", 2))
+	mock.ExpectRollback()
+
+	st := &store{db: db}
+	err = st.deleteProject(context.Background(), "billing")
+	if got := violations(err); got != "NOT_EMPTY:projects/billing" {
+		t.Errorf("deleteProject error = %v, want the project not empty", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,245 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// retryAfterHeader tells clients when to retry a rate limited call, the
+	// gateway returns it as Retry-After
+	retryAfterHeader = "retry-after"
+
+	// limiters idle this long are dropped, they would be full again anyway
+	limiterIdleTimeout = 10 * time.Minute
+)
+
+// RateLimit is a token bucket refilled at Rate calls per second holding up to
+// Burst calls. The zero value does not limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) unlimited() bool {
+	return l.Rate == 0 && l.Burst == 0
+}
+
+// Validate rejects limits that would refuse every call for good, a bucket
+// has to refill and hold at least one call
+func (l RateLimit) Validate() error {
+	switch {
+	case l.unlimited():
+		return nil
+	case l.Rate < 0 || math.IsNaN(l.Rate) || math.IsInf(l.Rate, 0):
+		return fmt.Errorf("rate %v must be a positive number", l.Rate)
+	case l.Rate == 0:
+		return fmt.Errorf("rate must be above 0 when burst is %d, 0:0 is unlimited", l.Burst)
+	case l.Burst < 1:
+		return fmt.Errorf("burst %d must be at least 1", l.Burst)
+	}
+	return nil
+}
+
+var rateLimitClients = prometheus.NewDesc(
+	"sreapi_ratelimit_clients",
+	"Clients with a rate limiter, by limit.",
+	[]string{"method"}, nil,
+)
+
+// limiterKey is a bucket, the limit it counts for, see limit, and the client
+// using it
+type limiterKey struct {
+	method string
+	client string
+}
+
+type clientLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per method and client
+type rateLimiter struct {
+	limits   map[string]RateLimit
+	fallback RateLimit
+	rejected *prometheus.CounterVec
+
+	mu        sync.Mutex
+	limiters  map[limiterKey]*clientLimiter
+	lastSweep time.Time
+}
+
+func newRateLimiter(reg prometheus.Registerer, limits map[string]RateLimit, fallback RateLimit) (*rateLimiter, error) {
+	for method, l := range limits {
+		if err := l.Validate(); err != nil {
+			return nil, fmt.Errorf("rate limit of %s: %v", method, err)
+		}
+	}
+	if err := fallback.Validate(); err != nil {
+		return nil, fmt.Errorf("default rate limit: %v", err)
+	}
+	rl := &rateLimiter{
+		limits:   limits,
+		fallback: fallback,
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sreapi",
+			Name:      "ratelimit_rejected_total",
+			Help:      "Calls rejected by the rate limiter, by method.",
+		}, []string{"method"}),
+		limiters: map[limiterKey]*clientLimiter{},
+	}
+	if err := reg.Register(rl.rejected); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(rl); err != nil {
+		return nil, err
+	}
+	return rl, nil
+}
+
+// limit finds the limit for fullMethod, by full name, then method name so
+// "List" covers every api version, then the fallback. The key names the
+// bucket: the entry that matched, or the method name for the fallback, so
+// calling another api version does not get a client a second budget.
+func (rl *rateLimiter) limit(fullMethod string) (l RateLimit, key string) {
+	if l, ok := rl.limits[fullMethod]; ok {
+		return l, fullMethod
+	}
+	_, method := splitMethod(fullMethod)
+	if l, ok := rl.limits[method]; ok && method != "" {
+		return l, method
+	}
+	return rl.fallback, method
+}
+
+// allow takes a token for client calling fullMethod, or says how long until
+// one is available
+func (rl *rateLimiter) allow(fullMethod, client string) (bool, time.Duration) {
+	l, key := rl.limit(fullMethod)
+	if l.unlimited() {
+		return true, 0
+	}
+
+	now := time.Now()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) > limiterIdleTimeout {
+		for k, cl := range rl.limiters {
+			if now.Sub(cl.lastSeen) > limiterIdleTimeout {
+				delete(rl.limiters, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	k := limiterKey{method: key, client: client}
+	cl, ok := rl.limiters[k]
+	if !ok {
+		cl = &clientLimiter{Limiter: rate.NewLimiter(rate.Limit(l.Rate), l.Burst)}
+		rl.limiters[k] = cl
+	}
+	cl.lastSeen = now
+
+	r := cl.ReserveN(now, 1)
+	if !r.OK() {
+		// Validate keeps limits from getting here
+		return false, 0
+	}
+	if d := r.DelayFrom(now); d > 0 {
+		r.CancelAt(now)
+		return false, d
+	}
+	return true, 0
+}
+
+func (rl *rateLimiter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- rateLimitClients
+}
+
+// Collect exports only counts, clients would give unbounded label values
+func (rl *rateLimiter) Collect(ch chan<- prometheus.Metric) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	clients := map[string]int{}
+	for k := range rl.limiters {
+		clients[k.method]++
+	}
+	for method, n := range clients {
+		ch <- prometheus.MustNewConstMetric(rateLimitClients, prometheus.GaugeValue, float64(n), method)
+	}
+}
+
+// clientKey identifies the caller, by the identity from Auth when there is
+// one, otherwise by address
+func clientKey(ctx context.Context) string {
+	if id, ok := Identity(ctx); ok && id != "" {
+		return id
+	}
+	if transport(ctx) != "grpc" {
+		// The gateway appends the REST client's address to x-forwarded-for
+		md, _ := metadata.FromIncomingContext(ctx)
+		if fwd := md.Get("x-forwarded-for"); len(fwd) > 0 {
+			addrs := strings.Split(fwd[len(fwd)-1], ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return "unknown"
+}
+
+// check rejects the call with ResourceExhausted when the caller is over its
+// limit, telling it when to retry in the retry-after header and RetryInfo
+func (rl *rateLimiter) check(ctx context.Context, fullMethod string) error {
+	ok, wait := rl.allow(fullMethod, clientKey(ctx))
+	if ok {
+		return nil
+	}
+	rl.rejected.WithLabelValues(fullMethod).Inc()
+
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	if wait > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(retryAfterHeader, strconv.Itoa(int(math.Ceil(wait.Seconds())))))
+		if ds, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+			st = ds
+		}
+	}
+	return st.Err()
+}
+
+func (rl *rateLimiter) interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := rl.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (rl *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := rl.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
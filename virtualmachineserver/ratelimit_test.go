@@ -0,0 +1,92 @@
+package virtualmachineserver
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRateLimitValidate(t *testing.T) {
+	tests := []struct {
+		limit RateLimit
+		valid bool
+	}{
+		{RateLimit{}, true},
+		{RateLimit{Rate: 5, Burst: 10}, true},
+		{RateLimit{Rate: 0.5, Burst: 1}, true},
+		{RateLimit{Rate: 5, Burst: 0}, false},
+		{RateLimit{Rate: 5, Burst: -1}, false},
+		{RateLimit{Rate: -1, Burst: 10}, false},
+		{RateLimit{Rate: 0, Burst: 10}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.limit.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%+v.Validate() = %v, want valid %v", tt.limit, err, tt.valid)
+		}
+	}
+}
+
+func TestNewRateLimiterRejectsBadLimits(t *testing.T) {
+	if _, err := newRateLimiter(prometheus.NewRegistry(), map[string]RateLimit{"List": {Rate: 1}}, RateLimit{}); err == nil {
+		t.Error("newRateLimiter accepted a method limit with burst 0")
+	}
+	if _, err := newRateLimiter(prometheus.NewRegistry(), nil, RateLimit{Rate: -1, Burst: 1}); err == nil {
+		t.Error("newRateLimiter accepted a negative default rate")
+	}
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	rl, err := newRateLimiter(prometheus.NewRegistry(), map[string]RateLimit{
+		"/sreapi.v2.Virtualmachines/Get": {Rate: 1, Burst: 1},
+		"List":                           {Rate: 2, Burst: 2},
+	}, RateLimit{Rate: 3, Burst: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method string
+		want   RateLimit
+		key    string
+	}{
+		{"/sreapi.v2.Virtualmachines/Get", RateLimit{Rate: 1, Burst: 1}, "/sreapi.v2.Virtualmachines/Get"},
+		{"/sreapi.Virtualmachines/Get", RateLimit{Rate: 3, Burst: 3}, "Get"},
+		{"/sreapi.v2.Virtualmachines/List", RateLimit{Rate: 2, Burst: 2}, "List"},
+		{"/sreapi.Virtualmachines/List", RateLimit{Rate: 2, Burst: 2}, "List"},
+		{"/sreapi.v2.Ipam/ListSubnets", RateLimit{Rate: 3, Burst: 3}, "ListSubnets"},
+	}
+	for _, tt := range tests {
+		l, key := rl.limit(tt.method)
+		if l != tt.want || key != tt.key {
+			t.Errorf("limit(%s) = %+v, %q, want %+v, %q", tt.method, l, key, tt.want, tt.key)
+		}
+	}
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl, err := newRateLimiter(prometheus.NewRegistry(), map[string]RateLimit{"List": {Rate: 0.001, Burst: 2}}, RateLimit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The burst is shared by every api version of List
+	for i, method := range []string{"/sreapi.Virtualmachines/List", "/sreapi.v2.Virtualmachines/List"} {
+		if ok, _ := rl.allow(method, "10.0.0.1"); !ok {
+			t.Fatalf("call %d to %s was limited within the burst", i+1, method)
+		}
+	}
+	ok, wait := rl.allow("/sreapi.v2.Virtualmachines/List", "10.0.0.1")
+	if ok {
+		t.Fatal("call past the burst was allowed")
+	}
+	if wait <= 0 {
+		t.Errorf("limited call has retry delay %s, want it positive", wait)
+	}
+
+	// Other clients and methods without limits are not affected
+	if ok, _ := rl.allow("/sreapi.v2.Virtualmachines/List", "10.0.0.2"); !ok {
+		t.Error("another client was limited")
+	}
+	if ok, _ := rl.allow("/sreapi.v2.Virtualmachines/Get", "10.0.0.1"); !ok {
+		t.Error("an unlimited method was limited")
+	}
+}
@@ -0,0 +1,158 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// roleServer manages the catalog of roles vms can have
+type roleServer struct {
+	store *store
+}
+
+// CreateRole role
+func (s *roleServer) CreateRole(ctx context.Context, in *pbv2.CreateRoleRequest) (*pbv2.Role, error) {
+	loggerFrom(ctx).Info("creating role", "name", in.Role.Name)
+	if err := s.store.createRole(ctx, in.Role); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusCreated)
+	return in.Role, nil
+}
+
+// GetRole role
+func (s *roleServer) GetRole(ctx context.Context, in *pbv2.GetRoleRequest) (*pbv2.Role, error) {
+	return s.store.getRole(ctx, in.Name)
+}
+
+// ListRoles by name
+func (s *roleServer) ListRoles(ctx context.Context, in *pbv2.ListRolesRequest) (*pbv2.ListRolesResponse, error) {
+	roles, err := s.store.listRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pbv2.ListRolesResponse{Roles: roles}, nil
+}
+
+// UpdateRole role, only the fields in update_mask are changed when it is
+// set. Vms that already have the role keep what they got from it.
+func (s *roleServer) UpdateRole(ctx context.Context, in *pbv2.UpdateRoleRequest) (*pbv2.Role, error) {
+	paths := in.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"description", "default_sizing", "default_labels", "allowed_projects", "classes"}
+	}
+	update := func(r *pbv2.Role) error {
+		for _, path := range paths {
+			switch path {
+			case "description":
+				r.Description = in.Role.Description
+			case "default_sizing":
+				r.DefaultSizing = in.Role.DefaultSizing
+			case "default_labels":
+				r.DefaultLabels = in.Role.DefaultLabels
+			case "allowed_projects":
+				r.AllowedProjects = in.Role.AllowedProjects
+			case "classes":
+				r.Classes = in.Role.Classes
+			case "name", "vm_count":
+				return invalidField("update_mask", fmt.Sprintf("%s cannot be updated", path))
+			default:
+				return invalidField("update_mask", fmt.Sprintf("unknown field %q", path))
+			}
+		}
+		if err := checkRoleFields(r, "role"); err != nil {
+			return err
+		}
+		loggerFrom(ctx).Info("updating role", "name", r.Name)
+		return nil
+	}
+	return s.store.updateRole(ctx, in.Name, update)
+}
+
+// DeleteRole role, which no vm may have
+func (s *roleServer) DeleteRole(ctx context.Context, in *pbv2.DeleteRoleRequest) (*empty.Empty, error) {
+	loggerFrom(ctx).Info("deleting role", "name", in.Name)
+	if err := s.store.deleteRole(ctx, in.Name); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusNoContent)
+	return &empty.Empty{}, nil
+}
+
+// checkRoleFields validates everything in r but its name
+func checkRoleFields(r *pbv2.Role, field string) error {
+	if len(r.Description) > maxDescription {
+		return invalidField(field+".description", fmt.Sprintf("description is longer than %d characters", maxDescription))
+	}
+	if s := r.DefaultSizing; s != nil && (s.Cpus < 0 || s.MemoryMb < 0 || s.DiskGb < 0) {
+		return invalidField(field+".default_sizing", "sizing must not be negative")
+	}
+	if err := checkLabels(r.DefaultLabels, field+".default_labels"); err != nil {
+		return err
+	}
+	for _, list := range []struct {
+		name   string
+		values []string
+	}{
+		{"allowed_projects", r.AllowedProjects},
+		{"classes", r.Classes},
+	} {
+		seen := map[string]bool{}
+		for i, v := range list.values {
+			f := fmt.Sprintf("%s.%s[%d]", field, list.name, i)
+			switch {
+			case v == "":
+				return invalidField(f, "must not be empty")
+			case len(v) > 255:
+				return invalidField(f, fmt.Sprintf("%q is longer than 255 characters", v))
+			case seen[v]:
+				return invalidField(f, fmt.Sprintf("%s is listed twice", v))
+			}
+			seen[v] = true
+		}
+	}
+	return nil
+}
+
+// validateRoleRequest checks the role requests, it is run by
+// validationInterceptor
+func validateRoleRequest(req interface{}) error {
+	switch in := req.(type) {
+	case *pbv2.CreateRoleRequest:
+		switch {
+		case in.Role == nil:
+			return invalidField("role", "role is required")
+		case in.Role.Name == "":
+			return invalidField("role.name", "name is required")
+		case !dnsLabel.MatchString(in.Role.Name):
+			return invalidField("role.name", fmt.Sprintf("role name %q must be 1 to 63 lower case letters, digits or '-', starting and ending with a letter or digit", in.Role.Name))
+		}
+		return checkRoleFields(in.Role, "role")
+	case *pbv2.GetRoleRequest:
+		return requireRole("name", in.Name)
+	case *pbv2.UpdateRoleRequest:
+		if err := requireRole("name", in.Name); err != nil {
+			return err
+		}
+		if in.Role == nil {
+			return invalidField("role", "role is required")
+		}
+		if in.Role.Name != "" && in.Role.Name != in.Name {
+			return invalidField("role.name", "roles cannot be renamed")
+		}
+	case *pbv2.DeleteRoleRequest:
+		return requireRole("name", in.Name)
+	}
+	return nil
+}
+
+func requireRole(field, role string) error {
+	if role == "" {
+		return invalidField(field, "role is required")
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// violations lists the TYPE:subject of the precondition failures in err, or
+// "" when it is not FailedPrecondition
+func violations(err error) string {
+	st, _ := status.FromError(err)
+	if st.Code() != codes.FailedPrecondition {
+		return ""
+	}
+	var vs []string
+	for _, d := range st.Details() {
+		if pf, ok := d.(*errdetails.PreconditionFailure); ok {
+			for _, v := range pf.Violations {
+				vs = append(vs, v.Type+":"+v.Subject)
+			}
+		}
+	}
+	return strings.Join(vs, ",")
+}
+
+// labelString is labels as sorted name=value pairs
+func labelString(labels map[string]string) string {
+	var pairs []string
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// sizingString is the sizes that are set, e.g. 2cpu,4096MB
+func sizingString(s *pbv2.Sizing) string {
+	var parts []string
+	if s.GetCpus() > 0 {
+		parts = append(parts, fmt.Sprintf("%dcpu", s.Cpus))
+	}
+	if s.GetMemoryMb() > 0 {
+		parts = append(parts, fmt.Sprintf("%dMB", s.MemoryMb))
+	}
+	if s.GetDiskGb() > 0 {
+		parts = append(parts, fmt.Sprintf("%dGB", s.DiskGb))
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestFitRole(t *testing.T) {
+	role := &pbv2.Role{
+		Name:            "web",
+		DefaultLabels:   map[string]string{"env": "prod", "tier": "front"},
+		DefaultSizing:   &pbv2.Sizing{Cpus: 2, MemoryMb: 4096},
+		AllowedProjects: []string{"billing", "shop"},
+	}
+	tests := []struct {
+		name   string
+		vm     *pbv2.Virtualmachine
+		fill   bool
+		labels string
+		sizing string
+		err    string
+	}{
+		{"defaults filled", &pbv2.Virtualmachine{Project: "billing", Role: "web"}, true,
+			"env=prod,tier=front", "2cpu,4096MB", ""},
+		{"own labels win", &pbv2.Virtualmachine{Project: "billing", Role: "web", Labels: map[string]string{"env": "dev", "team": "pay"}}, true,
+			"env=dev,team=pay,tier=front", "2cpu,4096MB", ""},
+		{"own empty label wins", &pbv2.Virtualmachine{Project: "billing", Role: "web", Labels: map[string]string{"env": ""}}, true,
+			"env=,tier=front", "2cpu,4096MB", ""},
+		{"own sizing kept whole", &pbv2.Virtualmachine{Project: "billing", Role: "web", Sizing: &pbv2.Sizing{DiskGb: 40}}, true,
+			"env=prod,tier=front", "40GB", ""},
+		{"empty sizing is no sizing", &pbv2.Virtualmachine{Project: "billing", Role: "web", Sizing: &pbv2.Sizing{}}, true,
+			"env=prod,tier=front", "2cpu,4096MB", ""},
+		{"updates are not filled", &pbv2.Virtualmachine{Project: "shop", Role: "web"}, false,
+			"", "", ""},
+		{"project not allowed", &pbv2.Virtualmachine{Hostname: "web01", Project: "hr", Role: "web"}, true,
+			"", "", "ROLE:roles/web"},
+		{"project not allowed on update", &pbv2.Virtualmachine{Hostname: "web01", Project: "hr", Role: "web"}, false,
+			"", "", "ROLE:roles/web"},
+	}
+	for _, tt := range tests {
+		err := fitRole(role, tt.vm, tt.fill)
+		if tt.err != "" {
+			if got := violations(err); got != tt.err {
+				t.Errorf("%s: fitRole error = %v, want violation %s", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: fitRole error = %v", tt.name, err)
+			continue
+		}
+		if got := labelString(tt.vm.Labels); got != tt.labels {
+			t.Errorf("%s: labels = %q, want %q", tt.name, got, tt.labels)
+		}
+		if got := sizingString(tt.vm.Sizing); got != tt.sizing {
+			t.Errorf("%s: sizing = %q, want %q", tt.name, got, tt.sizing)
+		}
+	}
+
+	// The role's sizing is copied, not shared
+	vm := &pbv2.Virtualmachine{Project: "billing", Role: "web"}
+	fitRole(role, vm, true)
+	vm.Sizing.Cpus = 8
+	if role.DefaultSizing.Cpus != 2 {
+		t.Errorf("changing the vm's sizing changed the role's to %v", role.DefaultSizing)
+	}
+
+	// Any project is allowed without allowed projects
+	if err := fitRole(&pbv2.Role{Name: "db"}, &pbv2.Virtualmachine{Project: "hr", Role: "db"}, true); err != nil {
+		t.Errorf("fitRole without allowed projects: %v", err)
+	}
+}
+
+func TestSetRoleDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := regexp.QuoteMeta
+
+	for _, table := range roleTables {
+		mock.ExpectExec(q("DELETE FROM " + table + " WHERE Role = ?")).WithArgs("web").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(q("INSERT INTO role_labels")).WithArgs("web", "env", "prod").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Allowed projects are sorted and must exist
+	mock.ExpectQuery(q("SELECT 1 FROM projects")).WithArgs("billing").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectExec(q("INSERT INTO role_projects")).WithArgs("web", "billing").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(q("SELECT 1 FROM projects")).WithArgs("shop").WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	r := &pbv2.Role{
+		Name:            "web",
+		DefaultLabels:   map[string]string{"env": "prod"},
+		AllowedProjects: []string{"shop", "billing"},
+		Classes:         []string{"nginx"},
+	}
+	err = setRoleDetails(context.Background(), db, r)
+	if got := violations(err); got != "PROJECT:projects/shop" {
+		t.Errorf("setRoleDetails error = %v, want the missing project shop", err)
+	}
+	if strings.Join(r.AllowedProjects, ",") != "billing,shop" {
+		t.Errorf("allowed projects = %v, want them sorted", r.AllowedProjects)
+	}
+
+	for _, table := range roleTables {
+		mock.ExpectExec(q("DELETE FROM " + table + " WHERE Role = ?")).WithArgs("db").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(q("INSERT INTO role_classes")).WithArgs("db", 0, "postgres").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(q("INSERT INTO role_classes")).WithArgs("db", 1, "backup").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := setRoleDetails(context.Background(), db, &pbv2.Role{Name: "db", Classes: []string{"postgres", "backup"}}); err != nil {
+		t.Errorf("setRoleDetails: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteProjectAllowedByRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := regexp.QuoteMeta
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(q(projectColumns + " WHERE p.Name = ? FOR UPDATE")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Name", "OwnerTeam", "Contact", "CostCenter", "Description", "Vms"}).AddRow("billing", "pay", "", "", "", 0))
+	mock.ExpectQuery(q("SELECT Role FROM role_projects WHERE Project = ?")).WithArgs("billing").
+		WillReturnRows(sqlmock.NewRows([]string{"Role"}).AddRow("db").AddRow("web"))
+	mock.ExpectRollback()
+
+	st := &store{db: db}
+	err = st.deleteProject(context.Background(), "billing")
+	if got := violations(err); got != "IN_USE:roles/db,IN_USE:roles/web" {
+		t.Errorf("deleteProject error = %v, want the roles db and web in use", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
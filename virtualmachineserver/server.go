@@ -3,170 +3,454 @@ package virtualmachineserver
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/achanno/sreapi/certs"
+	"github.com/achanno/sreapi/inventory"
+	"github.com/achanno/sreapi/openapi"
 	pb "github.com/achanno/sreapi/protobuf"
-	"log"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/achanno/sreapi/tracing"
+	"log/slog"
 	"net"
 
 	// Needed
-	"flag"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
-	netcontext "golang.org/x/net/context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 const (
 	port = ":5555"
-	apiv = "v1"
 
 	dbhost = "127.0.0.1:3306"
 	dbuser = "sreapi"
 	dbpass = "tmp123"
+
+	defaultShutdownTimeout = 30 * time.Second
 )
 
-// Server t
-type Server struct{}
+// DefaultDSN is the database opened when Options sets neither DB nor DSN
+const DefaultDSN = dbuser + ":" + dbpass + "@tcp(" + dbhost + ")/sreapi?clientFoundRows=true"
+
+// Options for New, the zero value serves the local sreapi database on :5555
+type Options struct {
+	// Addr grpc listens on, the REST gateway shares it unless HTTPAddr is set.
+	// ":0" picks a free port, see Server.Addr.
+	Addr     string
+	HTTPAddr string
+	// DB is used as is and left open by Stop. Otherwise DSN is opened, it
+	// must set clientFoundRows=true.
+	DB  *sql.DB
+	DSN string
+	// Certificate served over TLS, the demo certificate when nil
+	Certificate *tls.Certificate
+	// ShutdownTimeout bounds how long Stop drains in-flight requests
+	ShutdownTimeout time.Duration
+	// Logger for the server, JSON on stderr at LogLevel (debug, info, warn
+	// or error) when nil
+	Logger   *slog.Logger
+	LogLevel string
+	// Registry the server's metrics are registered with, a new one with the
+	// Go and process collectors when nil. Servers cannot share one.
+	Registry *prometheus.Registry
+	// TraceExporter and TraceFile set up the global tracer provider in
+	// Serve, New uses whichever provider is installed
+	TraceExporter string
+	TraceFile     string
+	// Auth authenticates every call when set
+	Auth AuthFunc
+	// RateLimits limit each client's calls per method. Keys are full method
+	// names like /sreapi.v2.Virtualmachines/List, or method names like List
+	// for every api version, which share one budget. Other methods get
+	// DefaultRateLimit, again one budget per method name. Clients are told
+	// apart by the identity from Auth, or their address.
+	RateLimits       map[string]RateLimit
+	DefaultRateLimit RateLimit
+	// DNSAddr serves A, AAAA and PTR queries for the vms over udp and tcp
+	// when set, names are the vms' hostnames in DNSZone. PTR queries are
+	// answered in the reverse zones of the ipam subnets.
+	DNSAddr string
+	DNSZone string
+	// ENCMapping is the file classifying vms for puppet and salt, served at
+	// /enc/<hostname> when set
+	ENCMapping string
+	// UnaryInterceptors and StreamInterceptors run after the built in
+	// logging, metrics, recovery, auth, rate limiting and validation
+	// interceptors
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+}
 
-var (
-	vmEndpoint   = flag.String("vm_endpoint", "localhost:5555", "vm service endpoint")
-	db           *sql.DB
-	demoKeyPair  *tls.Certificate
-	demoCertPool *x509.CertPool
-)
+// Server serves the vm api over grpc and the REST gateway. Any number of
+// Servers can run in one process.
+type Server struct {
+	opts    Options
+	db      *sql.DB
+	ownDB   bool
+	store   *store
+	logger  *slog.Logger
+	metrics *metrics
+	limiter *rateLimiter
+	unary   grpc.UnaryServerInterceptor
+	mux     *runtime.ServeMux
+	health  *health.Server
+	dns     *dnsResponder
+	grpc    *grpc.Server
+	http    *http.Server
+
+	lis     net.Listener
+	httpLis net.Listener
+	errc    chan error
+	cancel  context.CancelFunc
+
+	shuttingDown atomic.Bool
+	stopOnce     sync.Once
+	stopErr      error
+}
 
-func initDBConnection() {
-	var err error
-	db, err = sql.Open("mysql", dbuser+":"+dbpass+"@tcp("+dbhost+")/sreapi")
-	if err != nil {
-		log.Fatalf("Error opening db %v", dbhost)
+// newLogger logs JSON to stderr at level
+func newLogger(level string) (*slog.Logger, error) {
+	lv := new(slog.LevelVar)
+	if level != "" {
+		if err := lv.UnmarshalText([]byte(level)); err != nil {
+			return nil, err
+		}
 	}
+	return slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: lv})), nil
 }
 
-// List vms
-func (s *Server) List(ctx context.Context, in *pb.ListRequest) (*pb.ListResponse, error) {
-	var err error
-	var rows *sql.Rows
-	log.Println("List called with project: " + in.Project + " role: " + in.Role)
+// New builds a Server from opts, nothing listens until Start
+func New(opts Options) (*Server, error) {
+	if opts.Addr == "" {
+		opts.Addr = port
+	}
+	if opts.ShutdownTimeout == 0 {
+		opts.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if opts.Logger == nil {
+		l, err := newLogger(opts.LogLevel)
+		if err != nil {
+			return nil, err
+		}
+		opts.Logger = l
+	}
+	if opts.Certificate == nil {
+		pair, err := tls.X509KeyPair([]byte(certs.Cert), []byte(certs.Key))
+		if err != nil {
+			return nil, err
+		}
+		opts.Certificate = &pair
+	}
+	if opts.Registry == nil {
+		opts.Registry = prometheus.NewRegistry()
+		opts.Registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
 
-	if in.Project == "" && in.Role != "" {
-		rows, err = db.Query("SELECT * FROM vm WHERE Role like ?", in.Role)
-	} else if in.Project != "" && in.Role == "" {
-		rows, err = db.Query("SELECT * FROM vm WHERE Project like ?", in.Project)
-	} else if in.Project != "" && in.Role != "" {
-		rows, err = db.Query("SELECT * FROM vm WHERE Project like ? AND Role like ?", in.Project, in.Role)
+	s := &Server{opts: opts, logger: opts.Logger, db: opts.DB}
+	if s.db == nil {
+		dsn := opts.DSN
+		if dsn == "" {
+			dsn = DefaultDSN
+		}
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, err
+		}
+		s.db = db
+		s.ownDB = true
 	}
+	s.store = &store{db: s.db}
 
+	m, err := newMetrics(opts.Registry, s.db, s.logger)
 	if err != nil {
-		log.Println("Error selecting from db: ", err)
+		s.closeDB()
+		return nil, err
 	}
+	s.metrics = m
 
-	vms := make([]*pb.Virtualmachine, 0)
+	rl, err := newRateLimiter(opts.Registry, opts.RateLimits, opts.DefaultRateLimit)
+	if err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	s.limiter = rl
 
-	for rows.Next() {
-		newvm := new(pb.Virtualmachine)
-		err = rows.Scan(&newvm.Hostname, &newvm.Project, &newvm.Role)
+	if opts.DNSAddr != "" {
+		if opts.DNSZone == "" {
+			s.closeDB()
+			return nil, errors.New("DNSZone is required to serve dns")
+		}
+		d, err := newDNSResponder(opts.Registry, s.store, opts.DNSZone, s.logger)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			s.closeDB()
+			return nil, err
 		}
-		vms = append(vms, newvm)
+		s.dns = d
 	}
-	rows.Close()
-
-	log.Printf("Addr of vms: %p len: %d data: %v", vms, len(vms), &vms)
-	return &pb.ListResponse{XApi: apiv, Vms: vms}, nil
-	/*	var query, param1 string
-		if in.Project == "" && in.Role != "" {
-			query = "SELECT * FROM vm WHERE Role LIKE ?"
-			param1 = in.Role
-		} else if in.Project != "" && in.Role == "" {
-			query = "SELECT * FROM vm WHERE Project LIKE ?"
-			param1 = in.Project
-		} else if in.Project != "" && in.Role != "" {
-			query = "SELECT * FROM vm WHERE Project LIKE ? AND Role LIKE ?"
-			param1 = in.Project
+	if opts.ENCMapping != "" {
+		// Fail now rather than on the first node
+		if _, err := inventory.LoadENCMapping(opts.ENCMapping); err != nil {
+			s.closeDB()
+			return nil, err
 		}
+	}
 
-		rows, err := db.Query(query, param1)
-		if err != nil {
-			log.Printf("Error running query %v", err)
-		}
-		vms := make([]*pb.Virtualmachine, 0)
-		for rows.Next() {
-			vm := new(pb.Virtualmachine)
-			err = rows.Scan(&vm.Hostname, &vm.Project, &vm.Role)
-			if err != nil {
-				log.Printf("Error scanning row: %v", err)
-			}
-			vms = append(vms, vm)
+	s.health = health.NewServer()
+	vms := &vmServer{store: s.store}
+	vmsv2 := &vmServerV2{store: s.store}
+	ipam := &ipamServer{store: s.store}
+	projects := &projectServer{store: s.store}
+	roles := &roleServer{store: s.store}
+	unaryICs := s.unaryInterceptors()
+	unary := chainUnary(unaryICs...)
+	s.unary = unary
+
+	ctx := context.Background()
+	mux := runtime.NewServeMux(
+		runtime.WithForwardResponseOption(httpResponseModifier),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
+	s.mux = mux
+	if err := pb.RegisterVirtualmachinesHandlerServer(ctx, mux, &gatewayServer{srv: vms, ic: unary}); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	if err := pbv2.RegisterVirtualmachinesHandlerServer(ctx, mux, &gatewayServerV2{srv: vmsv2, ic: unary}); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	if err := pbv2.RegisterIpamHandlerServer(ctx, mux, &gatewayIpam{srv: ipam, ic: unary}); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	if err := pbv2.RegisterProjectsHandlerServer(ctx, mux, &gatewayProjects{srv: projects, ic: unary}); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+	if err := pbv2.RegisterRolesHandlerServer(ctx, mux, &gatewayRoles{srv: roles, ic: unary}); err != nil {
+		s.closeDB()
+		return nil, err
+	}
+
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/openapi.json", openapi.SpecHandler())
+	httpMux.Handle("/docs/", openapi.UIHandler())
+	httpMux.HandleFunc("/healthz", healthzHandler)
+	httpMux.HandleFunc("/readyz", s.readyzHandler)
+	httpMux.Handle("/metrics", promhttp.HandlerFor(opts.Registry, promhttp.HandlerOpts{}))
+	httpMux.Handle("/inventory/ansible", s.metrics.instrumentHTTP(s.httpEndpoint("AnsibleInventory", s.ansibleInventory)))
+	httpMux.Handle("/sd/prometheus", s.metrics.instrumentHTTP(s.httpEndpoint("PrometheusTargets", s.prometheusTargets)))
+	if opts.ENCMapping != "" {
+		httpMux.Handle("/enc/", s.metrics.instrumentHTTP(s.httpEndpoint("ClassifyNode", s.encNode)))
+	}
+	httpMux.Handle("/", otelhttp.NewHandler(s.metrics.instrumentHTTP(noContentBody(mux)), "gateway", otelhttp.WithSpanNameFormatter(httpSpanName)))
+
+	s.grpc = grpc.NewServer(
+		grpc.Creds(credentials.NewServerTLSFromCert(opts.Certificate)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryICs...),
+		grpc.ChainStreamInterceptor(s.streamInterceptors()...),
+	)
+	pb.RegisterVirtualmachinesServer(s.grpc, vms)
+	pbv2.RegisterVirtualmachinesServer(s.grpc, vmsv2)
+	pbv2.RegisterIpamServer(s.grpc, ipam)
+	pbv2.RegisterProjectsServer(s.grpc, projects)
+	pbv2.RegisterRolesServer(s.grpc, roles)
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+
+	if opts.HTTPAddr == "" {
+		// grpc and REST share one port, told apart by grpcHandler
+		s.http = &http.Server{
+			Handler: grpcHandler(s.grpc, httpMux),
+			TLSConfig: &tls.Config{
+				Certificates: []tls.Certificate{*opts.Certificate},
+				NextProtos:   []string{"h2"},
+			},
 		}
-		return &pb.ListResponse{XApi: apiv, Vms: vms}, nil*/
+	} else {
+		s.http = &http.Server{
+			Handler: httpMux,
+			TLSConfig: &tls.Config{
+				Certificates: []tls.Certificate{*opts.Certificate},
+				NextProtos:   []string{"h2", "http/1.1"},
+			},
+		}
+	}
+	return s, nil
 }
 
-// Get vm
-func (s *Server) Get(ctx context.Context, in *pb.GetRequest) (*pb.GetResponse, error) {
-	vm := new(pb.Virtualmachine)
-	log.Println("Get request for: " + in.Hostname)
-	rows, err := db.Query("SELECT Hostname, Project, Role  FROM vm WHERE Hostname LIKE ?", in.Hostname)
-	defer rows.Close()
+// Start creates the missing database tables, then listens and serves in the
+// background until Stop is called or ctx is done. Errors that stop serving
+// are sent on Err.
+func (s *Server) Start(ctx context.Context) error {
+	// Any call may need any table, so they are all there before the first
+	// call is accepted
+	mctx, cancel := context.WithTimeout(ctx, dbMigrateTimeout)
+	err := s.store.migrate(mctx)
+	cancel()
 	if err != nil {
-		log.Printf("Error selcting from db: %v", err)
+		return fmt.Errorf("could not create database tables: %w", err)
 	}
-	rows.Next()
-	err = rows.Scan(&vm.Hostname, &vm.Project, &vm.Role)
+
+	lis, err := net.Listen("tcp", s.opts.Addr)
 	if err != nil {
-		log.Println("Error scanning row: ", err)
+		return err
+	}
+	if s.opts.HTTPAddr != "" {
+		httpLis, err := net.Listen("tcp", s.opts.HTTPAddr)
+		if err != nil {
+			lis.Close()
+			return err
+		}
+		s.httpLis = httpLis
 	}
-	log.Printf("Found VM: hostname: %s project: %s role: %s", (*vm).Hostname, (*vm).Project, (*vm).Role)
-	rows.Close()
-	return &pb.GetResponse{XApi: apiv, Vm: vm}, nil
+	if s.dns != nil {
+		if err := s.dns.listen(s.opts.DNSAddr); err != nil {
+			lis.Close()
+			if s.httpLis != nil {
+				s.httpLis.Close()
+			}
+			return err
+		}
+	}
+	s.lis = lis
+
+	// Every goroutine below may send on errc once, which is made before any
+	// of them starts
+	var serving []func() error
+	if s.httpLis == nil {
+		serving = append(serving, func() error { return s.http.Serve(tls.NewListener(lis, s.http.TLSConfig)) })
+	} else {
+		serving = append(serving,
+			func() error { return s.grpc.Serve(lis) },
+			func() error { return s.http.Serve(tls.NewListener(s.httpLis, s.http.TLSConfig)) })
+	}
+	if s.dns != nil {
+		serving = append(serving, s.dns.udp.ActivateAndServe, s.dns.tcp.ActivateAndServe)
+	}
+	s.errc = make(chan error, len(serving))
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.watchDB(ctx)
+	go func() {
+		<-ctx.Done()
+		s.Stop()
+	}()
+	for _, f := range serving {
+		go s.serve(f)
+	}
+	s.logger.Info("serving", "addr", s.Addr().String(), "http_addr", s.HTTPAddr().String())
+	if s.dns != nil {
+		s.logger.Info("serving dns", "addr", s.DNSAddr().String(), "zone", s.opts.DNSZone)
+	}
+	return nil
 }
 
-// Create vm
-func (s *Server) Create(ctx context.Context, in *pb.CreateRequest) (*pb.CreateResponse, error) {
-	log.Println("Creating new vm... hostname: " + in.Hostname + " project: " + in.Project + " role: " + in.Role)
+// serve runs f, sending errors other than being stopped on errc
+func (s *Server) serve(f func() error) {
+	err := f()
+	if err == nil || errors.Is(err, http.ErrServerClosed) || errors.Is(err, grpc.ErrServerStopped) || s.shuttingDown.Load() {
+		return
+	}
+	s.errc <- err
+}
 
-	query := `INSERT INTO vm VALUES (?,?,?)`
-	rows, err := db.Query(query, in.Hostname, in.Project, in.Role)
+// Err receives errors that stopped the server from serving
+func (s *Server) Err() <-chan error {
+	return s.errc
+}
 
-	if err != nil {
-		return &pb.CreateResponse{XApi: apiv, Success: false}, err
-	}
-	rows.Close()
-	return &pb.CreateResponse{XApi: apiv, Success: true}, nil
+// Addr grpc is served on, once started
+func (s *Server) Addr() net.Addr {
+	return s.lis.Addr()
 }
 
-// Update vm
-func (s *Server) Update(ctx context.Context, in *pb.UpdateRequest) (*pb.UpdateResponse, error) {
-	log.Println("Updating vm... hostname: " + in.Hostname + " project: " + in.Project + " role: " + in.Role)
+// HTTPAddr the REST gateway is served on, once started
+func (s *Server) HTTPAddr() net.Addr {
+	if s.httpLis != nil {
+		return s.httpLis.Addr()
+	}
+	return s.lis.Addr()
+}
 
-	query := "UPDATE vm SET Hostname=?, Project=?, Role=? WHERE Hostname like ?"
-	rows, err := db.Query(query, in.Hostname, in.Project, in.Role, in.Oldhostname)
-	defer rows.Close()
-	if err != nil {
-		log.Println("Failed updating row: ", err)
-		return &pb.UpdateResponse{XApi: apiv, Success: false}, err
+// DNSAddr the dns responder is served on over udp and tcp, once started with
+// Options.DNSAddr set
+func (s *Server) DNSAddr() net.Addr {
+	if s.dns == nil {
+		return nil
 	}
-	return &pb.UpdateResponse{XApi: apiv, Success: true}, nil
+	return s.dns.udp.PacketConn.LocalAddr()
 }
 
-// Delete vm
-func (s *Server) Delete(ctx context.Context, in *pb.DeleteRequest) (*pb.DeleteResponse, error) {
-	log.Println("Deleting vm: " + in.Hostname)
-	query := "DELETE FROM vm WHERE Hostname like ?"
-	rows, err := db.Query(query, in.Hostname)
-	defer rows.Close()
-	if err != nil {
-		log.Println("Failed to delete row: ", err)
-		return &pb.DeleteResponse{XApi: apiv, Success: true}, err
+// Stop reports not ready, drains in-flight requests for up to
+// Options.ShutdownTimeout and closes the database. It is safe to call more
+// than once.
+func (s *Server) Stop() error {
+	s.stopOnce.Do(func() {
+		// Report not ready first so load balancers stop sending new requests
+		s.shuttingDown.Store(true)
+		s.health.Shutdown()
+
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), s.opts.ShutdownTimeout)
+		defer drainCancel()
+
+		drained := make(chan struct{})
+		go func() {
+			// Shutdown also waits for grpc calls served through grpcHandler
+			s.http.Shutdown(drainCtx)
+			if s.httpLis != nil {
+				s.grpc.GracefulStop()
+			}
+			if s.dns != nil && s.dns.udp != nil {
+				s.dns.shutdown(drainCtx)
+			}
+			close(drained)
+		}()
+
+		select {
+		case <-drained:
+			s.logger.Info("drained all requests")
+		case <-drainCtx.Done():
+			s.logger.Warn("shutdown timeout reached, closing remaining connections")
+			s.grpc.Stop()
+			s.http.Close()
+		}
+
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.stopErr = s.closeDB()
+	})
+	return s.stopErr
+}
+
+// closeDB closes the database if New opened it
+func (s *Server) closeDB() error {
+	if !s.ownDB {
+		return nil
 	}
-	return &pb.DeleteResponse{XApi: apiv, Success: true}, nil
+	return s.db.Close()
 }
 
 func grpcHandler(grpcServer *grpc.Server, otherHandler http.Handler) http.HandlerFunc {
@@ -179,59 +463,51 @@ func grpcHandler(grpcServer *grpc.Server, otherHandler http.Handler) http.Handle
 	})
 }
 
-// Serve starts grpc server
-func Serve(port string) error {
-	initDBConnection()
-	defer db.Close()
-
-	pair, err := tls.X509KeyPair([]byte(certs.Cert), []byte(certs.Key))
-
-	if err != nil {
-		log.Fatalf("Error setting up TLS: %v", err)
+// Serve runs a Server as the process's main job: it installs the logger as
+// the slog default and sets up tracing, then serves until SIGINT or SIGTERM
+// has drained it or serving fails.
+func Serve(opts Options) error {
+	if opts.Logger == nil {
+		l, err := newLogger(opts.LogLevel)
+		if err != nil {
+			return err
+		}
+		opts.Logger = l
 	}
+	slog.SetDefault(opts.Logger)
 
-	demoKeyPair = &pair
-	demoCertPool = x509.NewCertPool()
-	ok := demoCertPool.AppendCertsFromPEM([]byte(certs.Cert))
-	if !ok {
-		log.Fatalf("Error appending certs")
+	shutdownTracing, err := tracing.Setup(context.Background(), "sreapi", opts.TraceExporter, opts.TraceFile)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			opts.Logger.Error("error flushing traces", "error", err)
+		}
+	}()
 
-	opts := []grpc.ServerOption{grpc.Creds(credentials.NewClientTLSFromCert(demoCertPool, "localhost:5555"))}
-	dcreds := credentials.NewTLS(&tls.Config{
-		ServerName: "localhost:5555",
-		RootCAs:    demoCertPool,
-	})
-	dopts := []grpc.DialOption{grpc.WithTransportCredentials(dcreds)}
-
-	netctx := netcontext.Background()
-	netctx, cancel := netcontext.WithCancel(netctx)
-	defer cancel()
-
-	mux := runtime.NewServeMux()
-	err2 := pb.RegisterVirtualmachinesHandlerFromEndpoint(netctx, mux, *vmEndpoint, dopts)
-	if err2 != nil {
-		log.Fatalf("Error registering endpoint handler: %v", err)
+	s, err := New(opts)
+	if err != nil {
+		return err
 	}
 
-	s := grpc.NewServer(opts...)
-	pb.RegisterVirtualmachinesServer(s, &Server{})
-	reflection.Register(s)
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
 
-	srv := &http.Server{
-		Addr:    port,
-		Handler: grpcHandler(s, mux),
-		TLSConfig: &tls.Config{
-			Certificates:       []tls.Certificate{*demoKeyPair},
-			NextProtos:         []string{"h2"},
-			InsecureSkipVerify: true,
-		},
+	if err := s.Start(context.Background()); err != nil {
+		s.Stop()
+		return err
 	}
 
-	lis, err := net.Listen("tcp", port)
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+	select {
+	case err := <-s.Err():
+		s.Stop()
+		return err
+	case sig := <-sigc:
+		opts.Logger.Info("draining requests", "signal", sig.String(), "timeout", s.opts.ShutdownTimeout.String())
 	}
-
-	return srv.Serve(tls.NewListener(lis, srv.TLSConfig))
+	return s.Stop()
 }
@@ -0,0 +1,33 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// Tables are created before anything listens, so no call can find them
+// missing
+func TestStartCreatesTablesFirst(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS vm_labels").WillReturnError(errors.New("access denied"))
+
+	s := &Server{store: &store{db: db}, opts: Options{Addr: "256.0.0.1:0"}}
+	err = s.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "could not create database tables: access denied") {
+		t.Errorf("Start error = %v, want the tables not created", err)
+	}
+	if s.lis != nil {
+		t.Error("Start listened before creating the tables")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
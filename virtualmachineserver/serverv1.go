@@ -0,0 +1,76 @@
+package virtualmachineserver
+
+import (
+	"context"
+
+	pb "github.com/achanno/sreapi/protobuf"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+)
+
+const apiv = "v1"
+
+// vmServer implements the v1 api
+type vmServer struct {
+	store *store
+}
+
+// List vms
+func (s *vmServer) List(ctx context.Context, in *pb.ListRequest) (*pb.ListResponse, error) {
+	loggerFrom(ctx).Debug("listing vms", "project", in.Project, "role", in.Role)
+
+	found, err := s.store.listVMs(ctx, vmFilter{Project: in.Project, Role: in.Role, Like: true})
+	if err != nil {
+		return nil, err
+	}
+
+	vms := make([]*pb.Virtualmachine, 0, len(found))
+	for _, vm := range found {
+		vms = append(vms, &pb.Virtualmachine{Hostname: vm.Hostname, Project: vm.Project, Role: vm.Role})
+	}
+	return &pb.ListResponse{XApi: apiv, Vms: vms}, nil
+}
+
+// Get vm
+func (s *vmServer) Get(ctx context.Context, in *pb.GetRequest) (*pb.GetResponse, error) {
+	loggerFrom(ctx).Debug("getting vm", "hostname", in.Hostname)
+	vm, err := s.store.getVM(ctx, in.Hostname, true)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetResponse{XApi: apiv, Vm: &pb.Virtualmachine{Hostname: vm.Hostname, Project: vm.Project, Role: vm.Role}}, nil
+}
+
+// Create vm
+func (s *vmServer) Create(ctx context.Context, in *pb.CreateRequest) (*pb.CreateResponse, error) {
+	loggerFrom(ctx).Info("creating vm", "hostname", in.Hostname, "project", in.Project, "role", in.Role)
+
+	err := s.store.createVM(ctx, &pbv2.Virtualmachine{Hostname: in.Hostname, Project: in.Project, Role: in.Role}, "", false, "")
+	if err != nil {
+		return &pb.CreateResponse{XApi: apiv, Success: false}, err
+	}
+	return &pb.CreateResponse{XApi: apiv, Success: true}, nil
+}
+
+// Update vm
+func (s *vmServer) Update(ctx context.Context, in *pb.UpdateRequest) (*pb.UpdateResponse, error) {
+	loggerFrom(ctx).Info("updating vm", "oldhostname", in.Oldhostname, "hostname", in.Hostname, "project", in.Project, "role", in.Role)
+
+	_, err := s.store.updateVM(ctx, in.Oldhostname, func(vm *pbv2.Virtualmachine) error {
+		vm.Hostname, vm.Project, vm.Role = in.Hostname, in.Project, in.Role
+		return nil
+	}, true)
+	if err != nil {
+		return &pb.UpdateResponse{XApi: apiv, Success: false}, err
+	}
+	return &pb.UpdateResponse{XApi: apiv, Success: true}, nil
+}
+
+// Delete vm
+func (s *vmServer) Delete(ctx context.Context, in *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	loggerFrom(ctx).Info("deleting vm", "hostname", in.Hostname)
+	err := s.store.deleteVM(ctx, in.Hostname, true)
+	if err != nil {
+		return &pb.DeleteResponse{XApi: apiv, Success: false}, err
+	}
+	return &pb.DeleteResponse{XApi: apiv, Success: true}, nil
+}
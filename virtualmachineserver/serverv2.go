@@ -0,0 +1,381 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strconv"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// httpCodeHeader lets v2 handlers pick the gateway response status
+	httpCodeHeader = "x-http-code"
+
+	// maxPageSize caps List pages, maxBatchSize BatchCreate requests
+	maxPageSize  = 1000
+	maxBatchSize = 1000
+)
+
+// vmServerV2 implements the resource oriented v2 api
+type vmServerV2 struct {
+	store *store
+}
+
+// List vms, optionally filtered by project and role, a page at a time when
+// page_size is set
+func (s *vmServerV2) List(ctx context.Context, in *pbv2.ListVirtualmachinesRequest) (*pbv2.ListVirtualmachinesResponse, error) {
+	after, err := decodePageToken(in.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	size := int(in.PageSize)
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	f := vmFilter{Project: in.Project, Role: in.Role, After: after}
+	if size > 0 {
+		// One more than the page tells whether there is a next page
+		f.Limit = size + 1
+	}
+	vms, err := s.store.listVMs(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pbv2.ListVirtualmachinesResponse{Vms: vms}
+	if size > 0 && len(vms) > size {
+		resp.Vms = vms[:size]
+		resp.NextPageToken = encodePageToken(vms[size-1].Hostname)
+	}
+	return resp, nil
+}
+
+// Get vm
+func (s *vmServerV2) Get(ctx context.Context, in *pbv2.GetVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	return s.store.getVM(ctx, in.Hostname, false)
+}
+
+// Create vm
+func (s *vmServerV2) Create(ctx context.Context, in *pbv2.CreateVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	loggerFrom(ctx).Info("creating vm", "hostname", in.Vm.Hostname, "project", in.Vm.Project, "role", in.Vm.Role, "auto_hostname", in.AutoHostname)
+	if err := s.store.createVM(ctx, in.Vm, in.AllocateFrom, in.AutoHostname, in.ReservationToken); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusCreated)
+	return in.Vm, nil
+}
+
+// Update vm, only the fields in update_mask are changed when it is set
+func (s *vmServerV2) Update(ctx context.Context, in *pbv2.UpdateVirtualmachineRequest) (*pbv2.Virtualmachine, error) {
+	paths := in.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"hostname", "project", "role", "labels", "addresses", "sizing"}
+	}
+
+	loggerFrom(ctx).Info("updating vm", "hostname", in.Hostname, "update_mask", paths)
+	return s.store.updateVM(ctx, in.Hostname, func(vm *pbv2.Virtualmachine) error {
+		for _, path := range paths {
+			switch path {
+			case "hostname":
+				vm.Hostname = in.Vm.Hostname
+			case "project":
+				vm.Project = in.Vm.Project
+			case "role":
+				vm.Role = in.Vm.Role
+			case "labels":
+				vm.Labels = in.Vm.Labels
+			case "addresses":
+				vm.Addresses = in.Vm.Addresses
+			case "sizing":
+				vm.Sizing = in.Vm.Sizing
+			default:
+				return invalidField("update_mask", fmt.Sprintf("unknown field %q", path))
+			}
+		}
+		return validateVM(vm)
+	}, false)
+}
+
+// Delete vm
+func (s *vmServerV2) Delete(ctx context.Context, in *pbv2.DeleteVirtualmachineRequest) (*empty.Empty, error) {
+	loggerFrom(ctx).Info("deleting vm", "hostname", in.Hostname)
+	if err := s.store.deleteVM(ctx, in.Hostname, false); err != nil {
+		return nil, err
+	}
+	setHTTPCode(ctx, http.StatusNoContent)
+	return &empty.Empty{}, nil
+}
+
+// BatchCreate vms in one transaction
+func (s *vmServerV2) BatchCreate(ctx context.Context, in *pbv2.BatchCreateVirtualmachinesRequest) (*pbv2.BatchCreateVirtualmachinesResponse, error) {
+	loggerFrom(ctx).Info("creating vms", "count", len(in.Vms), "upsert", in.Upsert)
+	if err := s.store.batchCreateVMs(ctx, in.Vms, in.Upsert); err != nil {
+		return nil, err
+	}
+	return &pbv2.BatchCreateVirtualmachinesResponse{Vms: in.Vms}, nil
+}
+
+// Page tokens hold the last hostname of the previous page
+func encodePageToken(hostname string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(hostname))
+}
+
+func decodePageToken(token string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", invalidField("page_token", "invalid page token")
+	}
+	return string(b), nil
+}
+
+// validateRequest checks the fields v2 requests cannot do without, it is run
+// by validationInterceptor
+func validateRequest(req interface{}) error {
+	switch in := req.(type) {
+	case *pbv2.ListVirtualmachinesRequest:
+		if in.PageSize < 0 {
+			return invalidField("page_size", "page_size must not be negative")
+		}
+	case *pbv2.BatchCreateVirtualmachinesRequest:
+		return validateBatch(in.Vms)
+	case *pbv2.GetVirtualmachineRequest:
+		return requireHostname(in.Hostname)
+	case *pbv2.CreateVirtualmachineRequest:
+		if in.AllocateFrom != "" {
+			if err := requireCidr("allocate_from", in.AllocateFrom); err != nil {
+				return err
+			}
+		}
+		if in.AutoHostname {
+			if in.Vm != nil && in.Vm.Hostname != "" {
+				return invalidField("vm.hostname", "hostname must be empty with auto_hostname, the server picks it")
+			}
+			if in.ReservationToken != "" {
+				return invalidField("reservation_token", "reservation_token is for creating a vm with the hostname it reserved, not with auto_hostname")
+			}
+			return checkVMFields(in.Vm, "vm")
+		}
+		return validateVM(in.Vm)
+	case *pbv2.UpdateVirtualmachineRequest:
+		if in.Vm == nil {
+			return invalidField("vm", "vm is required")
+		}
+		return requireHostname(in.Hostname)
+	case *pbv2.DeleteVirtualmachineRequest:
+		return requireHostname(in.Hostname)
+	case *pbv2.GetNamingTemplateRequest:
+		return requireProject("project", in.Project)
+	case *pbv2.SetNamingTemplateRequest:
+		if in.NamingTemplate == nil {
+			return invalidField("naming_template", "naming_template is required")
+		}
+		if err := requireProject("naming_template.project", in.NamingTemplate.Project); err != nil {
+			return err
+		}
+		return checkNamingTemplate("naming_template.template", in.NamingTemplate.Template)
+	case *pbv2.AllocateHostnameRequest:
+		if err := requireProject("project", in.Project); err != nil {
+			return err
+		}
+		if in.Role == "" {
+			return invalidField("role", "role is required")
+		}
+	}
+	if err := validateIPAMRequest(req); err != nil {
+		return err
+	}
+	if err := validateProjectRequest(req); err != nil {
+		return err
+	}
+	return validateRoleRequest(req)
+}
+
+// invalidField is an InvalidArgument error naming the bad field in a
+// BadRequest detail
+func invalidField(field, description string) error {
+	st := status.New(codes.InvalidArgument, description)
+	br := &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: field, Description: description},
+	}}
+	if ds, err := st.WithDetails(br); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+func requireHostname(hostname string) error {
+	if hostname == "" {
+		return invalidField("hostname", "hostname is required")
+	}
+	return nil
+}
+
+func requireProject(field, project string) error {
+	if project == "" {
+		return invalidField(field, "project is required")
+	}
+	return nil
+}
+
+func validateVM(vm *pbv2.Virtualmachine) error {
+	return checkVM(vm, "vm")
+}
+
+// labelName is what label names may look like, as in kubernetes
+var labelName = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.\-/]{0,61}[A-Za-z0-9])?$`)
+
+func checkLabels(labels map[string]string, field string) error {
+	for name, value := range labels {
+		if !labelName.MatchString(name) {
+			return invalidField(field, fmt.Sprintf("label name %q must be 1 to 63 letters, digits, '-', '_', '.' or '/', starting and ending with a letter or digit", name))
+		}
+		if len(value) > 255 {
+			return invalidField(field, fmt.Sprintf("label %s is longer than 255 characters", name))
+		}
+	}
+	return nil
+}
+
+// checkVM validates vm, field names it in errors
+func checkVM(vm *pbv2.Virtualmachine, field string) error {
+	if vm != nil && vm.Hostname == "" {
+		return invalidField(field+".hostname", "hostname is required")
+	}
+	return checkVMFields(vm, field)
+}
+
+// checkVMFields validates everything in vm but its hostname. Addresses are
+// rewritten in their canonical form, so 0:0::1 is stored as ::1, and sorted.
+func checkVMFields(vm *pbv2.Virtualmachine, field string) error {
+	switch {
+	case vm == nil:
+		return invalidField(field, "vm is required")
+	case vm.Project == "":
+		return invalidField(field+".project", "project is required")
+	case vm.Role == "":
+		return invalidField(field+".role", "role is required")
+	}
+	if err := checkLabels(vm.Labels, field+".labels"); err != nil {
+		return err
+	}
+	if s := vm.Sizing; s != nil && (s.Cpus < 0 || s.MemoryMb < 0 || s.DiskGb < 0) {
+		return invalidField(field+".sizing", "sizing must not be negative")
+	}
+	for i, a := range vm.Addresses {
+		addr, err := netip.ParseAddr(a)
+		if err != nil || addr.Zone() != "" {
+			return invalidField(fmt.Sprintf("%s.addresses[%d]", field, i), fmt.Sprintf("%q is not an IPv4 or IPv6 address", a))
+		}
+		vm.Addresses[i] = addr.Unmap().String()
+	}
+	sortAddresses(vm.Addresses)
+	for i := 1; i < len(vm.Addresses); i++ {
+		if vm.Addresses[i] == vm.Addresses[i-1] {
+			return invalidField(field+".addresses", fmt.Sprintf("address %s is listed twice", vm.Addresses[i]))
+		}
+	}
+	return nil
+}
+
+// validateBatch checks every vm, naming the bad one by its index
+func validateBatch(vms []*pbv2.Virtualmachine) error {
+	if len(vms) > maxBatchSize {
+		return invalidField("vms", fmt.Sprintf("at most %d vms can be created at once", maxBatchSize))
+	}
+	seen := map[string]bool{}
+	owners := map[string]string{}
+	for i, vm := range vms {
+		field := fmt.Sprintf("vms[%d]", i)
+		if err := checkVM(vm, field); err != nil {
+			return err
+		}
+		if seen[vm.Hostname] {
+			return invalidField(field+".hostname", fmt.Sprintf("vm %q is in the batch twice", vm.Hostname))
+		}
+		seen[vm.Hostname] = true
+		for _, addr := range vm.Addresses {
+			if owner, ok := owners[addr]; ok {
+				return invalidField(field+".addresses", fmt.Sprintf("address %s is also given to vm %q", addr, owner))
+			}
+			owners[addr] = vm.Hostname
+		}
+	}
+	return nil
+}
+
+// setHTTPCode picks the status of a gateway response, calls over grpc are
+// left alone so the header never reaches grpc clients
+func setHTTPCode(ctx context.Context, code int) {
+	if transport(ctx) != "gateway" {
+		return
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(httpCodeHeader, strconv.Itoa(code)))
+}
+
+// httpResponseModifier writes the status code chosen with setHTTPCode
+func httpResponseModifier(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	vals := md.HeaderMD.Get(httpCodeHeader)
+	if len(vals) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(vals[0])
+	if err != nil {
+		return err
+	}
+
+	delete(md.HeaderMD, httpCodeHeader)
+	w.Header().Del("Grpc-Metadata-X-Http-Code")
+	if code == http.StatusNoContent {
+		w.Header().Del("Content-Type")
+	}
+	w.WriteHeader(code)
+	return nil
+}
+
+// noContentBody drops the body the gateway marshals after the handler chose
+// 204 No Content with setHTTPCode
+func noContentBody(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(&noContentWriter{ResponseWriter: w}, r)
+	})
+}
+
+type noContentWriter struct {
+	http.ResponseWriter
+	noBody bool
+}
+
+func (w *noContentWriter) WriteHeader(code int) {
+	w.noBody = code == http.StatusNoContent
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *noContentWriter) Write(b []byte) (int, error) {
+	if w.noBody {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *noContentWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
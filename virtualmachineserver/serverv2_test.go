@@ -0,0 +1,139 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// A masked update reads the vm it changes under lock and writes it back in
+// the same transaction, so concurrent updates of other fields are kept
+func TestUpdateReadsAndWritesInOneTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := regexp.QuoteMeta
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(q("SELECT Hostname, Project, Role FROM vm WHERE Hostname = ? LIMIT 1 FOR UPDATE")).WithArgs("web01").
+		WillReturnRows(sqlmock.NewRows([]string{"Hostname", "Project", "Role"}).AddRow("web01", "billing", "web"))
+	mock.ExpectQuery(q("FROM vm_labels")).WillReturnRows(sqlmock.NewRows([]string{"Hostname", "Name", "Value"}).AddRow("web01", "env", "prod"))
+	mock.ExpectQuery(q("FROM vm_addresses")).WillReturnRows(sqlmock.NewRows([]string{"Hostname", "Address"}).AddRow("web01", "10.0.0.5"))
+	mock.ExpectQuery(q("FROM vm_sizing")).WillReturnRows(sqlmock.NewRows([]string{"Hostname", "Cpus", "MemoryMb", "DiskGb"}))
+	mock.ExpectQuery(q("SELECT 1 FROM projects")).WithArgs("billing").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery(q("SELECT 1 FROM roles")).WithArgs("web").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery(q(roleColumns)).WithArgs("web").
+		WillReturnRows(sqlmock.NewRows([]string{"Name", "Description", "Cpus", "MemoryMb", "DiskGb", "Vms"}).AddRow("web", "", 0, 0, 0, 1))
+	for _, table := range roleTables {
+		mock.ExpectQuery(q("FROM " + table)).WillReturnRows(sqlmock.NewRows([]string{"Role", "A", "B"}))
+	}
+	mock.ExpectExec(q("UPDATE vm SET")).WithArgs("web01", "billing", "web", "web01").WillReturnResult(sqlmock.NewResult(0, 1))
+	for i := 0; i < 2; i++ {
+		for _, table := range vmTables {
+			mock.ExpectExec(q("DELETE FROM " + table)).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+	mock.ExpectExec(q("INSERT INTO vm_labels")).WithArgs("web01", "team", "pay").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(q("INSERT INTO vm_addresses")).WithArgs("web01", "10.0.0.5").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	s := &vmServerV2{store: &store{db: db}}
+	vm, err := s.Update(context.Background(), &pbv2.UpdateVirtualmachineRequest{
+		Hostname:   "web01",
+		Vm:         &pbv2.Virtualmachine{Labels: map[string]string{"team": "pay"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if vm.Hostname != "web01" || vm.Project != "billing" || vm.Role != "web" || vm.Labels["team"] != "pay" || len(vm.Labels) != 1 || len(vm.Addresses) != 1 {
+		t.Errorf("Update = %v, want web01 in billing with role web, only label team=pay and its address", vm)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// noContentProjects answers deletes with 204 No Content
+type noContentProjects struct {
+	*pbv2.UnimplementedProjectsServer
+}
+
+func (noContentProjects) DeleteProject(ctx context.Context, in *pbv2.DeleteProjectRequest) (*empty.Empty, error) {
+	setHTTPCode(ctx, http.StatusNoContent)
+	return &empty.Empty{}, nil
+}
+
+func TestHTTPCode(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithForwardResponseOption(httpResponseModifier),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
+	passthrough := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(ctx, req)
+	}
+	err := pbv2.RegisterProjectsHandlerServer(context.Background(), mux, &gatewayProjects{srv: noContentProjects{}, ic: passthrough})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	noContentBody(mux).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v2/projects/billing", nil))
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("DELETE = %d %q, want 204 without a body", w.Code, w.Body.String())
+	}
+	for name := range w.Header() {
+		if name == "Content-Type" || name == "Grpc-Metadata-X-Http-Code" {
+			t.Errorf("DELETE has header %s", name)
+		}
+	}
+
+	// Over grpc the header is not set at all
+	stream := &runtime.ServerTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	if _, err := (noContentProjects{}).DeleteProject(ctx, &pbv2.DeleteProjectRequest{Name: "billing"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.Header()) != 0 {
+		t.Errorf("grpc call got headers %v", stream.Header())
+	}
+}
+
+func TestCheckLabels(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		valid  bool
+	}{
+		{nil, true},
+		{map[string]string{"env": "prod", "k8s.io/zone": "eu-west-1", "a": ""}, true},
+		{map[string]string{"team_name": strings.Repeat("x", 255)}, true},
+		{map[string]string{strings.Repeat("n", 63): "x"}, true},
+		{map[string]string{strings.Repeat("n", 64): "x"}, false},
+		{map[string]string{"": "x"}, false},
+		{map[string]string{"-env": "x"}, false},
+		{map[string]string{"env.": "x"}, false},
+		{map[string]string{"env name": "x"}, false},
+		{map[string]string{"env": strings.Repeat("x", 256)}, false},
+	}
+	for _, tt := range tests {
+		err := checkLabels(tt.labels, "vm.labels")
+		if (err == nil) != tt.valid {
+			t.Errorf("checkLabels(%v) error = %v, want valid %v", tt.labels, err, tt.valid)
+		}
+		if err != nil && violationField(err) != "vm.labels" {
+			t.Errorf("checkLabels(%v) field = %q, want vm.labels", tt.labels, violationField(err))
+		}
+	}
+}
@@ -0,0 +1,1738 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pbv2 "github.com/achanno/sreapi/protobuf/v2"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mysqlDuplicateEntry is returned by mysql when a unique key already exists
+const mysqlDuplicateEntry = 1062
+
+// vmFilter selects vms by project and role, empty fields match everything.
+// Like matches with sql LIKE patterns as the v1 api always has. After and
+// Limit page through the vms in hostname order.
+type vmFilter struct {
+	Project string
+	Role    string
+	Like    bool
+	After   string
+	Limit   int
+}
+
+func (f vmFilter) where() (string, []interface{}) {
+	op := " = ?"
+	if f.Like {
+		op = " LIKE ?"
+	}
+
+	var conds []string
+	var args []interface{}
+	if f.Project != "" {
+		conds = append(conds, "Project"+op)
+		args = append(args, f.Project)
+	}
+	if f.Role != "" {
+		conds = append(conds, "Role"+op)
+		args = append(args, f.Role)
+	}
+	if f.After != "" {
+		conds = append(conds, "Hostname > ?")
+		args = append(args, f.After)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// schema creates the tables sreapi added after the vm table, it is run once
+// the database is reachable
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS vm_labels (
+		Hostname VARCHAR(255) NOT NULL,
+		Name VARCHAR(63) NOT NULL,
+		Value VARCHAR(255) NOT NULL,
+		PRIMARY KEY (Hostname, Name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS vm_addresses (
+		Hostname VARCHAR(255) NOT NULL,
+		Address VARCHAR(45) NOT NULL,
+		PRIMARY KEY (Hostname, Address),
+		UNIQUE KEY (Address)
+	)`,
+	`CREATE TABLE IF NOT EXISTS subnets (
+		Cidr VARCHAR(49) NOT NULL PRIMARY KEY,
+		Description VARCHAR(255) NOT NULL,
+		Gateway VARCHAR(45) NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS subnet_pools (
+		Cidr VARCHAR(49) NOT NULL,
+		Start VARCHAR(45) NOT NULL,
+		End VARCHAR(45) NOT NULL,
+		PRIMARY KEY (Cidr, Start)
+	)`,
+	`CREATE TABLE IF NOT EXISTS naming_templates (
+		Project VARCHAR(255) NOT NULL PRIMARY KEY,
+		Template VARCHAR(255) NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS hostname_reservations (
+		Hostname VARCHAR(255) NOT NULL PRIMARY KEY,
+		Project VARCHAR(255) NOT NULL,
+		Role VARCHAR(255) NOT NULL,
+		Token VARCHAR(64) NOT NULL,
+		Expires DATETIME NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS projects (
+		Name VARCHAR(255) NOT NULL PRIMARY KEY,
+		OwnerTeam VARCHAR(255) NOT NULL,
+		Contact VARCHAR(255) NOT NULL,
+		CostCenter VARCHAR(255) NOT NULL,
+		Description TEXT NOT NULL
+	)`,
+	// vms created before projects were tracked keep their projects
+	`INSERT IGNORE INTO projects (Name, OwnerTeam, Contact, CostCenter, Description)
+		SELECT DISTINCT Project, '', '', '', '' FROM vm`,
+	`CREATE TABLE IF NOT EXISTS vm_sizing (
+		Hostname VARCHAR(255) NOT NULL PRIMARY KEY,
+		Cpus INT NOT NULL,
+		MemoryMb INT NOT NULL,
+		DiskGb INT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS roles (
+		Name VARCHAR(255) NOT NULL PRIMARY KEY,
+		Description TEXT NOT NULL,
+		Cpus INT NOT NULL,
+		MemoryMb INT NOT NULL,
+		DiskGb INT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS role_labels (
+		Role VARCHAR(255) NOT NULL,
+		Name VARCHAR(63) NOT NULL,
+		Value VARCHAR(255) NOT NULL,
+		PRIMARY KEY (Role, Name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS role_projects (
+		Role VARCHAR(255) NOT NULL,
+		Project VARCHAR(255) NOT NULL,
+		PRIMARY KEY (Role, Project)
+	)`,
+	`CREATE TABLE IF NOT EXISTS role_classes (
+		Role VARCHAR(255) NOT NULL,
+		Position INT NOT NULL,
+		Class VARCHAR(255) NOT NULL,
+		PRIMARY KEY (Role, Position)
+	)`,
+	// and their roles
+	`INSERT IGNORE INTO roles (Name, Description, Cpus, MemoryMb, DiskGb)
+		SELECT DISTINCT Role, '', 0, 0, 0 FROM vm`,
+}
+
+// vmTables hold the rows that belong to a vm, keyed by its hostname
+var vmTables = []string{"vm_labels", "vm_addresses", "vm_sizing"}
+
+// roleTables hold the rows that belong to a role, keyed by its name
+var roleTables = []string{"role_labels", "role_projects", "role_classes"}
+
+// detailChunk bounds the hostnames looked up in one query
+const detailChunk = 1000
+
+const (
+	// reservationTTL is how long AllocateHostname holds a hostname
+	reservationTTL = time.Hour
+	// hostnameAttempts bounds the retries when another call takes the
+	// hostname being allocated first
+	hostnameAttempts = 5
+)
+
+// errHostnameTaken is returned inside transactions that lost the race for
+// an allocated hostname, so they can be retried
+var errHostnameTaken = errors.New("hostname taken")
+
+// store reads and writes the inventory in mysql
+type store struct {
+	db *sql.DB
+}
+
+// querier is a *sql.DB or *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// migrate creates missing tables
+func (st *store) migrate(ctx context.Context) error {
+	for _, stmt := range schema {
+		if _, err := st.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tx runs f in a transaction, committed when f succeeds
+func (st *store) tx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return dbError(ctx, err)
+	}
+	return nil
+}
+
+// loadDetails fills in the labels and addresses of vms
+func loadDetails(ctx context.Context, q querier, vms []*pbv2.Virtualmachine) error {
+	byHost := map[string]*pbv2.Virtualmachine{}
+	for _, vm := range vms {
+		byHost[vm.Hostname] = vm
+	}
+
+	err := eachDetail(ctx, q, "SELECT Hostname, Name, Value FROM vm_labels", vms, func(hostname string, cols ...string) {
+		if vm, ok := byHost[hostname]; ok {
+			if vm.Labels == nil {
+				vm.Labels = map[string]string{}
+			}
+			vm.Labels[cols[0]] = cols[1]
+		}
+	}, 2)
+	if err != nil {
+		return err
+	}
+	err = eachDetail(ctx, q, "SELECT Hostname, Address FROM vm_addresses", vms, func(hostname string, cols ...string) {
+		if vm, ok := byHost[hostname]; ok {
+			vm.Addresses = append(vm.Addresses, cols[0])
+		}
+	}, 1)
+	if err != nil {
+		return err
+	}
+	err = eachDetail(ctx, q, "SELECT Hostname, Cpus, MemoryMb, DiskGb FROM vm_sizing", vms, func(hostname string, cols ...string) {
+		if vm, ok := byHost[hostname]; ok {
+			vm.Sizing = parseSizing(cols[0], cols[1], cols[2])
+		}
+	}, 3)
+	if err != nil {
+		return err
+	}
+	for _, vm := range vms {
+		sortAddresses(vm.Addresses)
+	}
+	return nil
+}
+
+func parseSizing(cpus, memoryMb, diskGb string) *pbv2.Sizing {
+	atoi := func(s string) int32 {
+		n, _ := strconv.Atoi(s)
+		return int32(n)
+	}
+	return &pbv2.Sizing{Cpus: atoi(cpus), MemoryMb: atoi(memoryMb), DiskGb: atoi(diskGb)}
+}
+
+// emptySizing reports whether s sets nothing, such sizings are not stored
+func emptySizing(s *pbv2.Sizing) bool {
+	return s == nil || s.Cpus == 0 && s.MemoryMb == 0 && s.DiskGb == 0
+}
+
+// sortAddresses puts addresses in numeric order, IPv4 first
+func sortAddresses(addrs []string) {
+	sort.SliceStable(addrs, func(i, j int) bool {
+		a, aerr := netip.ParseAddr(addrs[i])
+		b, berr := netip.ParseAddr(addrs[j])
+		if aerr != nil || berr != nil {
+			return addrs[i] < addrs[j]
+		}
+		return a.Less(b)
+	})
+}
+
+// eachDetail runs query for the hostnames of vms, a chunk at a time, calling
+// f with the hostname and the n other columns of each row
+func eachDetail(ctx context.Context, q querier, query string, vms []*pbv2.Virtualmachine, f func(hostname string, cols ...string), n int) error {
+	for start := 0; start < len(vms); start += detailChunk {
+		chunk := vms[start:min(start+detailChunk, len(vms))]
+		args := make([]interface{}, len(chunk))
+		for i, vm := range chunk {
+			args[i] = vm.Hostname
+		}
+		rows, err := q.QueryContext(ctx, query+" WHERE Hostname IN (?"+strings.Repeat(",?", len(chunk)-1)+") ORDER BY 1, 2", args...)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+
+		var hostname string
+		cols := make([]string, n)
+		dest := []interface{}{&hostname}
+		for i := range cols {
+			dest = append(dest, &cols[i])
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return dbError(ctx, err)
+			}
+			f(hostname, cols...)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	return nil
+}
+
+// setDetails replaces the labels, addresses and sizing of vm
+func setDetails(ctx context.Context, q querier, vm *pbv2.Virtualmachine) error {
+	if err := deleteDetails(ctx, q, vm.Hostname, " = ?"); err != nil {
+		return err
+	}
+	for name, value := range vm.Labels {
+		if _, err := q.ExecContext(ctx, "INSERT INTO vm_labels (Hostname, Name, Value) VALUES (?,?,?)", vm.Hostname, name, value); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	for _, addr := range vm.Addresses {
+		if err := addAddress(ctx, q, vm.Hostname, addr); err != nil {
+			return err
+		}
+	}
+	if emptySizing(vm.Sizing) {
+		vm.Sizing = nil
+		return nil
+	}
+	if _, err := q.ExecContext(ctx, "INSERT INTO vm_sizing (Hostname, Cpus, MemoryMb, DiskGb) VALUES (?,?,?,?)", vm.Hostname, vm.Sizing.Cpus, vm.Sizing.MemoryMb, vm.Sizing.DiskGb); err != nil {
+		return dbError(ctx, err)
+	}
+	return nil
+}
+
+// addAddress gives hostname addr, which no other vm may have
+func addAddress(ctx context.Context, q querier, hostname, addr string) error {
+	_, err := q.ExecContext(ctx, "INSERT INTO vm_addresses (Hostname, Address) VALUES (?,?)", hostname, addr)
+	if isDuplicate(err) {
+		var owner string
+		if err := q.QueryRowContext(ctx, "SELECT Hostname FROM vm_addresses WHERE Address = ?", addr).Scan(&owner); err != nil {
+			return dbError(ctx, err)
+		}
+		return addressInUse(addr, owner)
+	}
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	return nil
+}
+
+// deleteDetails deletes the rows belonging to the vms matching hostname with
+// op
+func deleteDetails(ctx context.Context, q querier, hostname, op string) error {
+	for _, table := range vmTables {
+		if _, err := q.ExecContext(ctx, "DELETE FROM "+table+" WHERE Hostname"+op, hostname); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	return nil
+}
+
+func (st *store) listVMs(ctx context.Context, f vmFilter) ([]*pbv2.Virtualmachine, error) {
+	where, args := f.where()
+	query := "SELECT Hostname, Project, Role FROM vm" + where + " ORDER BY Hostname"
+	if f.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(f.Limit)
+	}
+	ctx, span := dbSpan(ctx, "list vms", query)
+	defer span.End()
+
+	rows, err := st.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	vms := make([]*pbv2.Virtualmachine, 0)
+	for rows.Next() {
+		vm := new(pbv2.Virtualmachine)
+		if err := rows.Scan(&vm.Hostname, &vm.Project, &vm.Role); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		vms = append(vms, vm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	rows.Close()
+
+	if err := loadDetails(ctx, st.db, vms); err != nil {
+		return nil, err
+	}
+	return vms, nil
+}
+
+// getVM reads the vm called hostname, or the first matching the pattern
+// with like, and with lock holds it until the transaction ends
+func getVM(ctx context.Context, q querier, hostname string, like, lock bool) (*pbv2.Virtualmachine, error) {
+	query := "SELECT Hostname, Project, Role FROM vm WHERE Hostname = ?"
+	if like {
+		query = "SELECT Hostname, Project, Role FROM vm WHERE Hostname LIKE ?"
+	}
+	query += " LIMIT 1"
+	if lock {
+		query += " FOR UPDATE"
+	}
+
+	vm := new(pbv2.Virtualmachine)
+	err := q.QueryRowContext(ctx, query, hostname).Scan(&vm.Hostname, &vm.Project, &vm.Role)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "vm %q not found", hostname)
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	if err := loadDetails(ctx, q, []*pbv2.Virtualmachine{vm}); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+func (st *store) getVM(ctx context.Context, hostname string, like bool) (*pbv2.Virtualmachine, error) {
+	ctx, span := dbSpan(ctx, "get vm", "SELECT Hostname, Project, Role FROM vm")
+	defer span.End()
+	return getVM(ctx, st.db, hostname, like, false)
+}
+
+// vmByAddress returns the vm with address addr
+func (st *store) vmByAddress(ctx context.Context, addr string) (*pbv2.Virtualmachine, error) {
+	query := "SELECT vm.Hostname, vm.Project, vm.Role FROM vm JOIN vm_addresses a ON a.Hostname = vm.Hostname WHERE a.Address = ? ORDER BY vm.Hostname LIMIT 1"
+	ctx, span := dbSpan(ctx, "get vm by address", query)
+	defer span.End()
+
+	vm := new(pbv2.Virtualmachine)
+	err := st.db.QueryRowContext(ctx, query, addr).Scan(&vm.Hostname, &vm.Project, &vm.Role)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "no vm has address %s", addr)
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	if err := loadDetails(ctx, st.db, []*pbv2.Virtualmachine{vm}); err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+// createVM creates vm, with its next free address in the subnet allocateFrom
+// when that is set. auto names vm from its project's naming template, token
+// lets it take a hostname reserved with AllocateHostname.
+func (st *store) createVM(ctx context.Context, vm *pbv2.Virtualmachine, allocateFrom string, auto bool, token string) error {
+	query := "INSERT INTO vm (Hostname, Project, Role) VALUES (?,?,?)"
+	ctx, span := dbSpan(ctx, "create vm", query)
+	defer span.End()
+
+	create := func(tx *sql.Tx, vm *pbv2.Virtualmachine) error {
+		if err := checkProject(ctx, tx, vm.Project, vm.Hostname); err != nil {
+			return err
+		}
+		if err := applyRole(ctx, tx, vm, true); err != nil {
+			return err
+		}
+		if auto {
+			hostname, err := nextHostname(ctx, tx, vm.Project, vm.Role)
+			if err != nil {
+				return err
+			}
+			vm.Hostname = hostname
+		}
+		_, err := tx.ExecContext(ctx, query, vm.Hostname, vm.Project, vm.Role)
+		if isDuplicate(err) {
+			if auto {
+				return errHostnameTaken
+			}
+			return vmExists(vm.Hostname)
+		}
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		if err := claimReservation(ctx, tx, vm, token); err != nil {
+			return err
+		}
+		if err := setDetails(ctx, tx, vm); err != nil {
+			return err
+		}
+		if allocateFrom == "" {
+			return nil
+		}
+		addr, err := allocateIP(ctx, tx, vm.Hostname, allocateFrom, "")
+		if err != nil {
+			return err
+		}
+		vm.Addresses = append(vm.Addresses, addr)
+		sortAddresses(vm.Addresses)
+		return nil
+	}
+
+	// Each attempt starts from the vm as it was given
+	for i := 0; i < hostnameAttempts; i++ {
+		created := proto.Clone(vm).(*pbv2.Virtualmachine)
+		err := st.tx(ctx, func(tx *sql.Tx) error { return create(tx, created) })
+		if err == errHostnameTaken {
+			continue
+		}
+		if err == nil {
+			vm.Hostname, vm.Labels, vm.Addresses, vm.Sizing = created.Hostname, created.Labels, created.Addresses, created.Sizing
+		}
+		return err
+	}
+	return status.Errorf(codes.Aborted, "could not allocate a hostname in project %q, other vms were created at the same time, try again", vm.Project)
+}
+
+// batchCreateVMs creates all of vms or none, upsert replaces existing vms
+func (st *store) batchCreateVMs(ctx context.Context, vms []*pbv2.Virtualmachine, upsert bool) error {
+	query := "INSERT INTO vm (Hostname, Project, Role) VALUES (?,?,?)"
+	if upsert {
+		query += " ON DUPLICATE KEY UPDATE Project = VALUES(Project), Role = VALUES(Role)"
+	}
+	ctx, span := dbSpan(ctx, "batch create vms", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		defer stmt.Close()
+
+		reserved, err := liveReservations(ctx, tx)
+		if err != nil {
+			return err
+		}
+		checked := map[string]bool{}
+		roles := map[string]*pbv2.Role{}
+		for _, vm := range vms {
+			if !checked[vm.Project] {
+				if err := checkProject(ctx, tx, vm.Project, vm.Hostname); err != nil {
+					return err
+				}
+				checked[vm.Project] = true
+			}
+			r, ok := roles[vm.Role]
+			if !ok {
+				if r, err = lockRole(ctx, tx, vm); err != nil {
+					return err
+				}
+				roles[vm.Role] = r
+			}
+			if err := fitRole(r, vm, true); err != nil {
+				return err
+			}
+			// Batches carry no reservation tokens, so reserved names wait
+			// for their reservation to expire
+			if r, ok := reserved[vm.Hostname]; ok {
+				return hostnameReserved(vm, r)
+			}
+			_, err := stmt.ExecContext(ctx, vm.Hostname, vm.Project, vm.Role)
+			if isDuplicate(err) {
+				return vmExists(vm.Hostname)
+			}
+			if err != nil {
+				return dbError(ctx, err)
+			}
+			if err := setDetails(ctx, tx, vm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// updateVM reads the vm called hostname, changes it with update and writes
+// it back in one transaction. v1 (like) renames keep the labels and addresses
+// while v2 sets those in vm.
+func (st *store) updateVM(ctx context.Context, hostname string, update func(*pbv2.Virtualmachine) error, like bool) (*pbv2.Virtualmachine, error) {
+	query := "UPDATE vm SET Hostname=?, Project=?, Role=? WHERE Hostname = ?"
+	if like {
+		query = "UPDATE vm SET Hostname=?, Project=?, Role=? WHERE Hostname LIKE ?"
+	}
+
+	ctx, span := dbSpan(ctx, "update vm", query)
+	defer span.End()
+
+	var vm *pbv2.Virtualmachine
+	err := st.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if vm, err = getVM(ctx, tx, hostname, like, true); err != nil {
+			return err
+		}
+		if err := update(vm); err != nil {
+			return err
+		}
+		if err := checkProject(ctx, tx, vm.Project, vm.Hostname); err != nil {
+			return err
+		}
+		if err := applyRole(ctx, tx, vm, false); err != nil {
+			return err
+		}
+		if vm.Hostname != hostname {
+			if err := claimReservation(ctx, tx, vm, ""); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx, query, vm.Hostname, vm.Project, vm.Role, hostname)
+		if isDuplicate(err) {
+			return vmExists(vm.Hostname)
+		}
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		if err := mustAffect(ctx, res, hostname); err != nil {
+			return err
+		}
+
+		if like {
+			for _, table := range vmTables {
+				_, err := tx.ExecContext(ctx, "UPDATE "+table+" SET Hostname=? WHERE Hostname LIKE ?", vm.Hostname, hostname)
+				if err != nil {
+					return dbError(ctx, err)
+				}
+			}
+			return nil
+		}
+		if err := deleteDetails(ctx, tx, hostname, " = ?"); err != nil {
+			return err
+		}
+		return setDetails(ctx, tx, vm)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vm, nil
+}
+
+func (st *store) deleteVM(ctx context.Context, hostname string, like bool) error {
+	op := " = ?"
+	if like {
+		op = " LIKE ?"
+	}
+	query := "DELETE FROM vm WHERE Hostname" + op
+
+	ctx, span := dbSpan(ctx, "delete vm", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, hostname)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		if err := mustAffect(ctx, res, hostname); err != nil {
+			return err
+		}
+		return deleteDetails(ctx, tx, hostname, op)
+	})
+}
+
+// checkProject fails with FailedPrecondition unless project exists, naming
+// the vm hostname being written in the error when it is set. The project's
+// row stays locked against deletion until the transaction q ends.
+func checkProject(ctx context.Context, q querier, project, hostname string) error {
+	var found int
+	err := q.QueryRowContext(ctx, "SELECT 1 FROM projects WHERE Name = ? LOCK IN SHARE MODE", project).Scan(&found)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return dbError(ctx, err)
+	}
+
+	st := status.Newf(codes.FailedPrecondition, "project %q does not exist", project)
+	pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+		{Type: "PROJECT", Subject: "projects/" + project, Description: "create the project first with 'sreapi project create'"},
+	}}
+	details := []proto.Message{pf}
+	if hostname != "" {
+		details = append(details, &errdetails.ResourceInfo{ResourceType: "vm", ResourceName: hostname, Description: fmt.Sprintf("project %q does not exist", project)})
+	}
+	if ds, err := st.WithDetails(details...); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+// projectColumns are selected by getProject and listProjects, with the
+// number of vms in the project
+const projectColumns = "SELECT p.Name, p.OwnerTeam, p.Contact, p.CostCenter, p.Description, (SELECT COUNT(*) FROM vm WHERE vm.Project = p.Name) FROM projects p"
+
+// getProject returns the project name, lock holds its row until the
+// transaction q ends
+func getProject(ctx context.Context, q querier, name string, lock bool) (*pbv2.Project, error) {
+	query := projectColumns + " WHERE p.Name = ?"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	p := new(pbv2.Project)
+	err := q.QueryRowContext(ctx, query, name).Scan(&p.Name, &p.OwnerTeam, &p.Contact, &p.CostCenter, &p.Description, &p.VmCount)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "project %q not found", name)
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return p, nil
+}
+
+func (st *store) getProject(ctx context.Context, name string) (*pbv2.Project, error) {
+	ctx, span := dbSpan(ctx, "get project", projectColumns)
+	defer span.End()
+	return getProject(ctx, st.db, name, false)
+}
+
+func (st *store) listProjects(ctx context.Context) ([]*pbv2.Project, error) {
+	query := projectColumns + " ORDER BY p.Name"
+	ctx, span := dbSpan(ctx, "list projects", query)
+	defer span.End()
+
+	rows, err := st.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	projects := make([]*pbv2.Project, 0)
+	for rows.Next() {
+		p := new(pbv2.Project)
+		if err := rows.Scan(&p.Name, &p.OwnerTeam, &p.Contact, &p.CostCenter, &p.Description, &p.VmCount); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return projects, nil
+}
+
+func (st *store) createProject(ctx context.Context, p *pbv2.Project) error {
+	query := "INSERT INTO projects (Name, OwnerTeam, Contact, CostCenter, Description) VALUES (?,?,?,?,?)"
+	ctx, span := dbSpan(ctx, "create project", query)
+	defer span.End()
+
+	_, err := st.db.ExecContext(ctx, query, p.Name, p.OwnerTeam, p.Contact, p.CostCenter, p.Description)
+	if isDuplicate(err) {
+		st := status.Newf(codes.AlreadyExists, "project %q already exists", p.Name)
+		if ds, err := st.WithDetails(&errdetails.ResourceInfo{ResourceType: "project", ResourceName: p.Name}); err == nil {
+			st = ds
+		}
+		return st.Err()
+	}
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	p.VmCount = 0
+	return nil
+}
+
+// updateProject changes the project name with update, which is given the
+// project read under lock in the same transaction
+func (st *store) updateProject(ctx context.Context, name string, update func(*pbv2.Project) error) (*pbv2.Project, error) {
+	query := "UPDATE projects SET OwnerTeam=?, Contact=?, CostCenter=?, Description=? WHERE Name = ?"
+	ctx, span := dbSpan(ctx, "update project", query)
+	defer span.End()
+
+	var p *pbv2.Project
+	err := st.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if p, err = getProject(ctx, tx, name, true); err != nil {
+			return err
+		}
+		if err := update(p); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, query, p.OwnerTeam, p.Contact, p.CostCenter, p.Description, name)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		return mustMatch(ctx, res, "project", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// deleteProject deletes the project name with its naming template and
+// hostname reservations, it must have no vms and no role may allow it
+func (st *store) deleteProject(ctx context.Context, name string) error {
+	query := "DELETE FROM projects WHERE Name = ?"
+	ctx, span := dbSpan(ctx, "delete project", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		p, err := getProject(ctx, tx, name, true)
+		if err != nil {
+			return err
+		}
+		if p.VmCount > 0 {
+			st := status.Newf(codes.FailedPrecondition, "project %q still has %d vms, delete or move them first", name, p.VmCount)
+			pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: "NOT_EMPTY", Subject: "projects/" + name, Description: fmt.Sprintf("%d vms are in the project", p.VmCount)},
+			}}
+			if ds, err := st.WithDetails(pf); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		// Dropping the project from its roles would let them be in any
+		// project when it was their only one
+		rows, err := tx.QueryContext(ctx, "SELECT Role FROM role_projects WHERE Project = ? ORDER BY Role LOCK IN SHARE MODE", name)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		var roles []string
+		for rows.Next() {
+			var role string
+			if err := rows.Scan(&role); err != nil {
+				rows.Close()
+				return dbError(ctx, err)
+			}
+			roles = append(roles, role)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return dbError(ctx, err)
+		}
+		if len(roles) > 0 {
+			st := status.Newf(codes.FailedPrecondition, "project %q is allowed by roles %s, remove it from them first", name, strings.Join(roles, ", "))
+			pf := &errdetails.PreconditionFailure{}
+			for _, role := range roles {
+				pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+					Type: "IN_USE", Subject: "roles/" + role, Description: fmt.Sprintf("role %s allows the project", role),
+				})
+			}
+			if ds, err := st.WithDetails(pf); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		for _, q := range []string{query, "DELETE FROM naming_templates WHERE Project = ?", "DELETE FROM hostname_reservations WHERE Project = ?"} {
+			if _, err := tx.ExecContext(ctx, q, name); err != nil {
+				return dbError(ctx, err)
+			}
+		}
+		return nil
+	})
+}
+
+// applyRole checks vm may have its role, which must exist and allow vm's
+// project, see fitRole. The role's row stays locked against deletion until
+// the transaction q ends.
+func applyRole(ctx context.Context, q querier, vm *pbv2.Virtualmachine, fill bool) error {
+	r, err := lockRole(ctx, q, vm)
+	if err != nil {
+		return err
+	}
+	return fitRole(r, vm, fill)
+}
+
+// lockRole loads vm's role, locking its row against deletion until the
+// transaction q ends
+func lockRole(ctx context.Context, q querier, vm *pbv2.Virtualmachine) (*pbv2.Role, error) {
+	var found int
+	err := q.QueryRowContext(ctx, "SELECT 1 FROM roles WHERE Name = ? LOCK IN SHARE MODE", vm.Role).Scan(&found)
+	if err == sql.ErrNoRows {
+		return nil, roleViolation(fmt.Sprintf("role %q does not exist", vm.Role), vm, "create the role first with 'sreapi role create'")
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return getRole(ctx, q, vm.Role, false)
+}
+
+// fitRole checks r allows vm's project. fill gives vm the role's default
+// labels, under its own, and the role's sizing when vm has none.
+func fitRole(r *pbv2.Role, vm *pbv2.Virtualmachine, fill bool) error {
+	if len(r.AllowedProjects) > 0 && !slices.Contains(r.AllowedProjects, vm.Project) {
+		return roleViolation(fmt.Sprintf("role %q is not allowed in project %q", vm.Role, vm.Project), vm, "allowed in "+strings.Join(r.AllowedProjects, ", "))
+	}
+	if !fill {
+		return nil
+	}
+	for name, value := range r.DefaultLabels {
+		if _, ok := vm.Labels[name]; !ok {
+			if vm.Labels == nil {
+				vm.Labels = map[string]string{}
+			}
+			vm.Labels[name] = value
+		}
+	}
+	if emptySizing(vm.Sizing) && !emptySizing(r.DefaultSizing) {
+		vm.Sizing = proto.Clone(r.DefaultSizing).(*pbv2.Sizing)
+	}
+	return nil
+}
+
+// roleViolation is FailedPrecondition for giving vm its role, naming the vm
+// so batch clients can tell which it was
+func roleViolation(msg string, vm *pbv2.Virtualmachine, description string) error {
+	st := status.New(codes.FailedPrecondition, msg)
+	pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+		{Type: "ROLE", Subject: "roles/" + vm.Role, Description: description},
+	}}
+	details := []proto.Message{pf}
+	if vm.Hostname != "" {
+		details = append(details, &errdetails.ResourceInfo{ResourceType: "vm", ResourceName: vm.Hostname, Description: msg})
+	}
+	if ds, err := st.WithDetails(details...); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+// roleColumns are selected by getRole and listRoles, with the number of
+// vms with the role
+const roleColumns = "SELECT r.Name, r.Description, r.Cpus, r.MemoryMb, r.DiskGb, (SELECT COUNT(*) FROM vm WHERE vm.Role = r.Name) FROM roles r"
+
+func scanRole(row interface{ Scan(...interface{}) error }) (*pbv2.Role, error) {
+	r := &pbv2.Role{DefaultSizing: &pbv2.Sizing{}}
+	err := row.Scan(&r.Name, &r.Description, &r.DefaultSizing.Cpus, &r.DefaultSizing.MemoryMb, &r.DefaultSizing.DiskGb, &r.VmCount)
+	if emptySizing(r.DefaultSizing) {
+		r.DefaultSizing = nil
+	}
+	return r, err
+}
+
+// loadRoleDetails fills in the default labels, allowed projects and classes
+// of roles, or only of the role name when it is set
+func loadRoleDetails(ctx context.Context, q querier, roles []*pbv2.Role, name string) error {
+	byName := map[string]*pbv2.Role{}
+	for _, r := range roles {
+		byName[r.Name] = r
+	}
+	each := func(query string, f func(r *pbv2.Role, a, b string)) error {
+		var args []interface{}
+		if name != "" {
+			query += " WHERE Role = ?"
+			args = append(args, name)
+		}
+		rows, err := q.QueryContext(ctx, query+" ORDER BY 1, 2", args...)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var role, a, b string
+			if err := rows.Scan(&role, &a, &b); err != nil {
+				return dbError(ctx, err)
+			}
+			if r, ok := byName[role]; ok {
+				f(r, a, b)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return dbError(ctx, err)
+		}
+		return nil
+	}
+
+	err := each("SELECT Role, Name, Value FROM role_labels", func(r *pbv2.Role, name, value string) {
+		if r.DefaultLabels == nil {
+			r.DefaultLabels = map[string]string{}
+		}
+		r.DefaultLabels[name] = value
+	})
+	if err != nil {
+		return err
+	}
+	err = each("SELECT Role, Project, '' FROM role_projects", func(r *pbv2.Role, project, _ string) {
+		r.AllowedProjects = append(r.AllowedProjects, project)
+	})
+	if err != nil {
+		return err
+	}
+	return each("SELECT Role, Position, Class FROM role_classes", func(r *pbv2.Role, _, class string) {
+		r.Classes = append(r.Classes, class)
+	})
+}
+
+// getRole returns the role name, lock holds its row until the transaction q
+// ends
+func getRole(ctx context.Context, q querier, name string, lock bool) (*pbv2.Role, error) {
+	query := roleColumns + " WHERE r.Name = ?"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	r, err := scanRole(q.QueryRowContext(ctx, query, name))
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "role %q not found", name)
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	if err := loadRoleDetails(ctx, q, []*pbv2.Role{r}, name); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (st *store) getRole(ctx context.Context, name string) (*pbv2.Role, error) {
+	ctx, span := dbSpan(ctx, "get role", roleColumns)
+	defer span.End()
+	return getRole(ctx, st.db, name, false)
+}
+
+func (st *store) listRoles(ctx context.Context) ([]*pbv2.Role, error) {
+	query := roleColumns + " ORDER BY r.Name"
+	ctx, span := dbSpan(ctx, "list roles", query)
+	defer span.End()
+
+	rows, err := st.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	roles := make([]*pbv2.Role, 0)
+	for rows.Next() {
+		r, err := scanRole(rows)
+		if err != nil {
+			return nil, dbError(ctx, err)
+		}
+		roles = append(roles, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	rows.Close()
+
+	if err := loadRoleDetails(ctx, st.db, roles, ""); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// setRoleDetails replaces the default labels, allowed projects and classes
+// of r, the allowed projects must exist
+func setRoleDetails(ctx context.Context, q querier, r *pbv2.Role) error {
+	for _, table := range roleTables {
+		if _, err := q.ExecContext(ctx, "DELETE FROM "+table+" WHERE Role = ?", r.Name); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	for name, value := range r.DefaultLabels {
+		if _, err := q.ExecContext(ctx, "INSERT INTO role_labels (Role, Name, Value) VALUES (?,?,?)", r.Name, name, value); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	sort.Strings(r.AllowedProjects)
+	for _, project := range r.AllowedProjects {
+		if err := checkProject(ctx, q, project, ""); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, "INSERT INTO role_projects (Role, Project) VALUES (?,?)", r.Name, project); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	for i, class := range r.Classes {
+		if _, err := q.ExecContext(ctx, "INSERT INTO role_classes (Role, Position, Class) VALUES (?,?,?)", r.Name, i, class); err != nil {
+			return dbError(ctx, err)
+		}
+	}
+	return nil
+}
+
+// sizingColumns are the role's default sizing as stored, 0 when unset
+func sizingColumns(s *pbv2.Sizing) (int32, int32, int32) {
+	if s == nil {
+		return 0, 0, 0
+	}
+	return s.Cpus, s.MemoryMb, s.DiskGb
+}
+
+func (st *store) createRole(ctx context.Context, r *pbv2.Role) error {
+	query := "INSERT INTO roles (Name, Description, Cpus, MemoryMb, DiskGb) VALUES (?,?,?,?,?)"
+	ctx, span := dbSpan(ctx, "create role", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		cpus, memoryMb, diskGb := sizingColumns(r.DefaultSizing)
+		_, err := tx.ExecContext(ctx, query, r.Name, r.Description, cpus, memoryMb, diskGb)
+		if isDuplicate(err) {
+			st := status.Newf(codes.AlreadyExists, "role %q already exists", r.Name)
+			if ds, err := st.WithDetails(&errdetails.ResourceInfo{ResourceType: "role", ResourceName: r.Name}); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		if emptySizing(r.DefaultSizing) {
+			r.DefaultSizing = nil
+		}
+		r.VmCount = 0
+		return setRoleDetails(ctx, tx, r)
+	})
+}
+
+// updateRole changes the role name with update, which is given the role read
+// under lock in the same transaction
+func (st *store) updateRole(ctx context.Context, name string, update func(*pbv2.Role) error) (*pbv2.Role, error) {
+	query := "UPDATE roles SET Description=?, Cpus=?, MemoryMb=?, DiskGb=? WHERE Name = ?"
+	ctx, span := dbSpan(ctx, "update role", query)
+	defer span.End()
+
+	var r *pbv2.Role
+	err := st.tx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if r, err = getRole(ctx, tx, name, true); err != nil {
+			return err
+		}
+		if err := update(r); err != nil {
+			return err
+		}
+		cpus, memoryMb, diskGb := sizingColumns(r.DefaultSizing)
+		res, err := tx.ExecContext(ctx, query, r.Description, cpus, memoryMb, diskGb, name)
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		if err := mustMatch(ctx, res, "role", name); err != nil {
+			return err
+		}
+		if emptySizing(r.DefaultSizing) {
+			r.DefaultSizing = nil
+		}
+		return setRoleDetails(ctx, tx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// deleteRole deletes the role name, no vm may have it
+func (st *store) deleteRole(ctx context.Context, name string) error {
+	query := "DELETE FROM roles WHERE Name = ?"
+	ctx, span := dbSpan(ctx, "delete role", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		r, err := getRole(ctx, tx, name, true)
+		if err != nil {
+			return err
+		}
+		if r.VmCount > 0 {
+			st := status.Newf(codes.FailedPrecondition, "role %q is used by %d vms, delete them or change their role first", name, r.VmCount)
+			pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: "IN_USE", Subject: "roles/" + name, Description: fmt.Sprintf("%d vms have the role", r.VmCount)},
+			}}
+			if ds, err := st.WithDetails(pf); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		if _, err := tx.ExecContext(ctx, query, name); err != nil {
+			return dbError(ctx, err)
+		}
+		for _, table := range roleTables {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE Role = ?", name); err != nil {
+				return dbError(ctx, err)
+			}
+		}
+		return nil
+	})
+}
+
+// namingTemplate returns the template project's vms are named with
+func namingTemplate(ctx context.Context, q querier, project string) (string, error) {
+	var tmpl string
+	err := q.QueryRowContext(ctx, "SELECT Template FROM naming_templates WHERE Project = ?", project).Scan(&tmpl)
+	if err == sql.ErrNoRows {
+		return defaultNamingTemplate, nil
+	}
+	if err != nil {
+		return "", dbError(ctx, err)
+	}
+	return tmpl, nil
+}
+
+func (st *store) setNamingTemplate(ctx context.Context, project, tmpl string) error {
+	query := "INSERT INTO naming_templates (Project, Template) VALUES (?,?) ON DUPLICATE KEY UPDATE Template = VALUES(Template)"
+	ctx, span := dbSpan(ctx, "set naming template", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		if _, err := getProject(ctx, tx, project, true); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, project, tmpl); err != nil {
+			return dbError(ctx, err)
+		}
+		return nil
+	})
+}
+
+// likeEscape quotes the LIKE wildcards in s
+var likeEscape = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// nextHostname is the hostname with the lowest number the project's
+// template gives that no vm has and no one has reserved
+func nextHostname(ctx context.Context, q querier, project, role string) (string, error) {
+	tmpl, err := namingTemplate(ctx, q, project)
+	if err != nil {
+		return "", err
+	}
+	prefix, suffix, width, err := expandTemplate(tmpl, project, role)
+	if err != nil {
+		return "", status.Errorf(codes.FailedPrecondition, "naming template of project %q: %v", project, err)
+	}
+
+	pattern := likeEscape.Replace(prefix) + "%" + likeEscape.Replace(suffix)
+	rows, err := q.QueryContext(ctx, "SELECT Hostname FROM vm WHERE Hostname LIKE ? UNION SELECT Hostname FROM hostname_reservations WHERE Hostname LIKE ? AND Expires > UTC_TIMESTAMP()", pattern, pattern)
+	if err != nil {
+		return "", dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	used := map[int]bool{}
+	for rows.Next() {
+		var hostname string
+		if err := rows.Scan(&hostname); err != nil {
+			return "", dbError(ctx, err)
+		}
+		if n, ok := hostnameNumber(hostname, prefix, suffix); ok {
+			used[n] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", dbError(ctx, err)
+	}
+	return numberedHostname(prefix, suffix, width, lowestFree(used)), nil
+}
+
+// allocateHostname reserves the next free hostname for a vm in project with
+// role, for reservationTTL or until a vm is created with it and the token it
+// returns
+func (st *store) allocateHostname(ctx context.Context, project, role string) (hostname, token string, err error) {
+	query := "INSERT INTO hostname_reservations (Hostname, Project, Role, Token, Expires) VALUES (?,?,?,?,DATE_ADD(UTC_TIMESTAMP(), INTERVAL ? SECOND))"
+	ctx, span := dbSpan(ctx, "allocate hostname", query)
+	defer span.End()
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", status.Errorf(codes.Internal, "could not make a reservation token: %v", err)
+	}
+	token = hex.EncodeToString(b)
+
+	reserve := func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM hostname_reservations WHERE Expires <= UTC_TIMESTAMP()"); err != nil {
+			return dbError(ctx, err)
+		}
+		if err := checkProject(ctx, tx, project, ""); err != nil {
+			return err
+		}
+		// Only reserve names a vm could be created with
+		if err := applyRole(ctx, tx, &pbv2.Virtualmachine{Project: project, Role: role}, false); err != nil {
+			return err
+		}
+		var err error
+		hostname, err = nextHostname(ctx, tx, project, role)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, query, hostname, project, role, token, int(reservationTTL.Seconds()))
+		if isDuplicate(err) {
+			return errHostnameTaken
+		}
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		return nil
+	}
+
+	for i := 0; i < hostnameAttempts; i++ {
+		if err := st.tx(ctx, reserve); err != errHostnameTaken {
+			if err != nil {
+				return "", "", err
+			}
+			return hostname, token, nil
+		}
+	}
+	return "", "", status.Errorf(codes.Aborted, "could not allocate a hostname in project %q, other hostnames were allocated at the same time, try again", project)
+}
+
+// reservation is who a hostname is held for
+type reservation struct {
+	project, role, token string
+}
+
+// claimReservation lets vm take its hostname when it is not held, or held for
+// vm's project and role with token, in which case the reservation is used
+// up. Names held for anyone else are refused until their reservation expires.
+func claimReservation(ctx context.Context, q querier, vm *pbv2.Virtualmachine, token string) error {
+	var r reservation
+	err := q.QueryRowContext(ctx, "SELECT Project, Role, Token FROM hostname_reservations WHERE Hostname = ? AND Expires > UTC_TIMESTAMP() FOR UPDATE", vm.Hostname).Scan(&r.project, &r.role, &r.token)
+	if err != nil && err != sql.ErrNoRows {
+		return dbError(ctx, err)
+	}
+	if err == nil && (token == "" || subtle.ConstantTimeCompare([]byte(r.token), []byte(token)) != 1 || r.project != vm.Project || r.role != vm.Role) {
+		return hostnameReserved(vm, r)
+	}
+	// Expired reservations of the name go too
+	if _, err := q.ExecContext(ctx, "DELETE FROM hostname_reservations WHERE Hostname = ?", vm.Hostname); err != nil {
+		return dbError(ctx, err)
+	}
+	return nil
+}
+
+// liveReservations are the hostnames held by reservations that have not
+// expired, locked until the transaction q ends
+func liveReservations(ctx context.Context, q querier) (map[string]reservation, error) {
+	rows, err := q.QueryContext(ctx, "SELECT Hostname, Project, Role FROM hostname_reservations WHERE Expires > UTC_TIMESTAMP() FOR UPDATE")
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	reserved := map[string]reservation{}
+	for rows.Next() {
+		var hostname string
+		var r reservation
+		if err := rows.Scan(&hostname, &r.project, &r.role); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		reserved[hostname] = r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return reserved, nil
+}
+
+// hostnameReserved is FailedPrecondition for creating vm with a hostname
+// reserved by someone else
+func hostnameReserved(vm *pbv2.Virtualmachine, r reservation) error {
+	msg := fmt.Sprintf("hostname %q is reserved for a vm in project %q with role %q, create it with the reservation token AllocateHostname returned", vm.Hostname, r.project, r.role)
+	st := status.New(codes.FailedPrecondition, msg)
+	pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+		{Type: "RESERVED", Subject: "vms/" + vm.Hostname, Description: fmt.Sprintf("reserved by AllocateHostname for up to %s", reservationTTL)},
+	}}
+	if ds, err := st.WithDetails(pf, &errdetails.ResourceInfo{ResourceType: "vm", ResourceName: vm.Hostname, Description: msg}); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+// canonicalCidr is cidr as netip prints it, so 2001:DB8::/64 and
+// 2001:db8::/64 name the same subnet
+func canonicalCidr(cidr string) string {
+	if p, err := netip.ParsePrefix(cidr); err == nil {
+		return p.String()
+	}
+	return cidr
+}
+
+// canonicalAddr is addr as netip prints it
+func canonicalAddr(addr string) string {
+	if a, err := netip.ParseAddr(addr); err == nil {
+		return a.Unmap().String()
+	}
+	return addr
+}
+
+// createSubnet stores sn, which may not overlap another subnet
+func (st *store) createSubnet(ctx context.Context, sn *pbv2.Subnet) error {
+	sn.Cidr = canonicalCidr(sn.Cidr)
+	if sn.Gateway != "" {
+		sn.Gateway = canonicalAddr(sn.Gateway)
+	}
+	for _, pool := range sn.Pools {
+		pool.Start, pool.End = canonicalAddr(pool.Start), canonicalAddr(pool.End)
+	}
+	p, err := netip.ParsePrefix(sn.Cidr)
+	if err != nil {
+		return invalidField("subnet.cidr", err.Error())
+	}
+
+	query := "INSERT INTO subnets (Cidr, Description, Gateway) VALUES (?,?,?)"
+	ctx, span := dbSpan(ctx, "create subnet", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		// Concurrent creates check overlaps one after the other
+		cidrs, err := subnetCidrs(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if c := overlapping(p, cidrs); c != "" {
+			return status.Errorf(codes.FailedPrecondition, "subnet %s overlaps subnet %s", sn.Cidr, c)
+		}
+
+		_, err = tx.ExecContext(ctx, query, sn.Cidr, sn.Description, sn.Gateway)
+		if isDuplicate(err) {
+			st := status.Newf(codes.AlreadyExists, "subnet %s already exists", sn.Cidr)
+			if ds, err := st.WithDetails(&errdetails.ResourceInfo{ResourceType: "subnet", ResourceName: sn.Cidr}); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		if err != nil {
+			return dbError(ctx, err)
+		}
+		for _, pool := range sn.Pools {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO subnet_pools (Cidr, Start, End) VALUES (?,?,?)", sn.Cidr, pool.Start, pool.End); err != nil {
+				return dbError(ctx, err)
+			}
+		}
+		return nil
+	})
+}
+
+// subnetCidrs lists every subnet, locked until the transaction q ends
+func subnetCidrs(ctx context.Context, q querier) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SELECT Cidr FROM subnets FOR UPDATE")
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	var cidrs []string
+	for rows.Next() {
+		var cidr string
+		if err := rows.Scan(&cidr); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return cidrs, nil
+}
+
+// listSubnets returns every subnet, in address order
+func (st *store) listSubnets(ctx context.Context) ([]*pbv2.Subnet, error) {
+	query := "SELECT Cidr, Description, Gateway FROM subnets"
+	ctx, span := dbSpan(ctx, "list subnets", query)
+	defer span.End()
+
+	rows, err := st.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	subnets := make([]*pbv2.Subnet, 0)
+	byCidr := map[string]*pbv2.Subnet{}
+	for rows.Next() {
+		sn := new(pbv2.Subnet)
+		if err := rows.Scan(&sn.Cidr, &sn.Description, &sn.Gateway); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		subnets = append(subnets, sn)
+		byCidr[sn.Cidr] = sn
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	rows.Close()
+
+	err = eachPool(ctx, st.db, "SELECT Cidr, Start, End FROM subnet_pools", func(cidr string, pool *pbv2.Pool) {
+		if sn, ok := byCidr[cidr]; ok {
+			sn.Pools = append(sn.Pools, pool)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(subnets, func(i, j int) bool {
+		a, aerr := netip.ParsePrefix(subnets[i].Cidr)
+		b, berr := netip.ParsePrefix(subnets[j].Cidr)
+		if aerr != nil || berr != nil {
+			return subnets[i].Cidr < subnets[j].Cidr
+		}
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+	return subnets, nil
+}
+
+// eachPool calls f with every pool query returns, in address order
+func eachPool(ctx context.Context, q querier, query string, f func(cidr string, pool *pbv2.Pool), args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		cidr string
+		pool *pbv2.Pool
+	}
+	var pools []row
+	for rows.Next() {
+		r := row{pool: new(pbv2.Pool)}
+		if err := rows.Scan(&r.cidr, &r.pool.Start, &r.pool.End); err != nil {
+			return dbError(ctx, err)
+		}
+		pools = append(pools, r)
+	}
+	if err := rows.Err(); err != nil {
+		return dbError(ctx, err)
+	}
+	sort.SliceStable(pools, func(i, j int) bool {
+		a, aerr := netip.ParseAddr(pools[i].pool.Start)
+		b, berr := netip.ParseAddr(pools[j].pool.Start)
+		if aerr != nil || berr != nil {
+			return pools[i].pool.Start < pools[j].pool.Start
+		}
+		return a.Less(b)
+	})
+	for _, r := range pools {
+		f(r.cidr, r.pool)
+	}
+	return nil
+}
+
+// getSubnet returns the subnet cidr, lock holds its row until the
+// transaction q ends so allocations from it happen one at a time
+func getSubnet(ctx context.Context, q querier, cidr string, lock bool) (*pbv2.Subnet, error) {
+	cidr = canonicalCidr(cidr)
+	query := "SELECT Cidr, Description, Gateway FROM subnets WHERE Cidr = ?"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	sn := new(pbv2.Subnet)
+	err := q.QueryRowContext(ctx, query, cidr).Scan(&sn.Cidr, &sn.Description, &sn.Gateway)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "subnet %s not found", cidr)
+	}
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	err = eachPool(ctx, q, "SELECT Cidr, Start, End FROM subnet_pools WHERE Cidr = ?", func(_ string, pool *pbv2.Pool) {
+		sn.Pools = append(sn.Pools, pool)
+	}, cidr)
+	if err != nil {
+		return nil, err
+	}
+	return sn, nil
+}
+
+// allocationsIn returns the vm addresses inside sn, in address order
+func allocationsIn(ctx context.Context, q querier, sn *pbv2.Subnet) ([]*pbv2.Allocation, error) {
+	p, err := netip.ParsePrefix(sn.Cidr)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "subnet %s has a bad cidr", sn.Cidr)
+	}
+	rows, err := q.QueryContext(ctx, "SELECT Hostname, Address FROM vm_addresses")
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	defer rows.Close()
+
+	allocs := make([]*pbv2.Allocation, 0)
+	for rows.Next() {
+		a := &pbv2.Allocation{Subnet: sn.Cidr}
+		if err := rows.Scan(&a.Hostname, &a.Address); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		if addr, err := netip.ParseAddr(a.Address); err == nil && p.Contains(addr) {
+			allocs = append(allocs, a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	sort.Slice(allocs, func(i, j int) bool {
+		return netip.MustParseAddr(allocs[i].Address).Less(netip.MustParseAddr(allocs[j].Address))
+	})
+	return allocs, nil
+}
+
+// deleteSubnet deletes the subnet cidr, which must have no addresses in use
+func (st *store) deleteSubnet(ctx context.Context, cidr string) error {
+	cidr = canonicalCidr(cidr)
+	query := "DELETE FROM subnets WHERE Cidr = ?"
+	ctx, span := dbSpan(ctx, "delete subnet", query)
+	defer span.End()
+
+	return st.tx(ctx, func(tx *sql.Tx) error {
+		sn, err := getSubnet(ctx, tx, cidr, true)
+		if err != nil {
+			return err
+		}
+		allocs, err := allocationsIn(ctx, tx, sn)
+		if err != nil {
+			return err
+		}
+		if len(allocs) > 0 {
+			st := status.Newf(codes.FailedPrecondition, "subnet %s still has %d addresses in use, release them first", cidr, len(allocs))
+			pf := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+				{Type: "IN_USE", Subject: cidr, Description: fmt.Sprintf("%d addresses are in use", len(allocs))},
+			}}
+			if ds, err := st.WithDetails(pf); err == nil {
+				st = ds
+			}
+			return st.Err()
+		}
+		if _, err := tx.ExecContext(ctx, query, cidr); err != nil {
+			return dbError(ctx, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM subnet_pools WHERE Cidr = ?", cidr); err != nil {
+			return dbError(ctx, err)
+		}
+		return nil
+	})
+}
+
+// allocateIP gives the vm hostname addr from the subnet cidr, or the
+// subnet's next free address when addr is empty. q must be a transaction.
+func allocateIP(ctx context.Context, q querier, hostname, cidr, addr string) (string, error) {
+	var found int
+	err := q.QueryRowContext(ctx, "SELECT 1 FROM vm WHERE Hostname = ?", hostname).Scan(&found)
+	if err == sql.ErrNoRows {
+		return "", status.Errorf(codes.NotFound, "vm %q not found", hostname)
+	}
+	if err != nil {
+		return "", dbError(ctx, err)
+	}
+
+	sn, err := getSubnet(ctx, q, cidr, true)
+	if err != nil {
+		return "", err
+	}
+	p, ranges, gw, err := parseSubnet(sn)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "subnet %s is invalid: %v", sn.Cidr, status.Convert(err).Message())
+	}
+
+	if addr != "" {
+		a, err := netip.ParseAddr(addr)
+		if err != nil || !p.Contains(a.Unmap()) {
+			return "", invalidField("address", fmt.Sprintf("%s is not an address in subnet %s", addr, sn.Cidr))
+		}
+		if a.Unmap() == gw {
+			return "", invalidField("address", fmt.Sprintf("%s is the gateway of subnet %s", addr, sn.Cidr))
+		}
+		addr = a.Unmap().String()
+	} else {
+		allocs, err := allocationsIn(ctx, q, sn)
+		if err != nil {
+			return "", err
+		}
+		used := make(map[netip.Addr]bool, len(allocs))
+		for _, a := range allocs {
+			used[netip.MustParseAddr(a.Address)] = true
+		}
+		a, ok := nextFree(ranges, gw, used)
+		if !ok {
+			return "", status.Errorf(codes.ResourceExhausted, "subnet %s has no free addresses", sn.Cidr)
+		}
+		addr = a.String()
+	}
+
+	if err := addAddress(ctx, q, hostname, addr); err != nil {
+		return "", err
+	}
+	return addr, nil
+}
+
+// releaseIP takes addr from the vm hostname
+func (st *store) releaseIP(ctx context.Context, hostname, addr string) error {
+	addr = canonicalAddr(addr)
+	query := "DELETE FROM vm_addresses WHERE Hostname = ? AND Address = ?"
+	ctx, span := dbSpan(ctx, "release address", query)
+	defer span.End()
+
+	res, err := st.db.ExecContext(ctx, query, hostname, addr)
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	if n == 0 {
+		return status.Errorf(codes.NotFound, "vm %q does not have address %s", hostname, addr)
+	}
+	return nil
+}
+
+// mustAffect returns NotFound when a write matched no vm
+func mustAffect(ctx context.Context, res sql.Result, hostname string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	if n == 0 {
+		return status.Errorf(codes.NotFound, "vm %q not found", hostname)
+	}
+	return nil
+}
+
+// mustMatch is NotFound when the update res matched no row of the resource
+// called name. The DSN sets clientFoundRows, so unchanged rows count.
+func mustMatch(ctx context.Context, res sql.Result, resource, name string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return dbError(ctx, err)
+	}
+	if n == 0 {
+		return status.Errorf(codes.NotFound, "%s %q not found", resource, name)
+	}
+	return nil
+}
+
+// addressInUse is the error for giving a vm an address owner already has
+func addressInUse(addr, owner string) error {
+	st := status.Newf(codes.AlreadyExists, "address %s is already used by vm %q", addr, owner)
+	if ds, err := st.WithDetails(&errdetails.ResourceInfo{ResourceType: "address", ResourceName: addr, Owner: owner}); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+// vmExists is AlreadyExists naming the vm in a ResourceInfo detail, so batch
+// clients can tell which vm it was
+func vmExists(hostname string) error {
+	st := status.Newf(codes.AlreadyExists, "vm %q already exists", hostname)
+	if ds, err := st.WithDetails(&errdetails.ResourceInfo{ResourceType: "vm", ResourceName: hostname}); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+func isDuplicate(err error) bool {
+	merr, ok := err.(*mysql.MySQLError)
+	return ok && merr.Number == mysqlDuplicateEntry
+}
+
+// dbError reports a failed query to the client without the driver details
+func dbError(ctx context.Context, err error) error {
+	loggerFrom(ctx).Error("database error", "error", err)
+	recordSpanError(ctx, err)
+	return status.Error(codes.Internal, "database error")
+}
@@ -0,0 +1,32 @@
+package virtualmachineserver
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/achanno/sreapi/virtualmachineserver")
+
+// dbSpan starts a client span for a query, dbError marks it failed
+func dbSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "db "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBName("sreapi"), semconv.DBStatement(query)),
+	)
+}
+
+// httpSpanName names gateway spans after the request
+func httpSpanName(_ string, r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+func recordSpanError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}